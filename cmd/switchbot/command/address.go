@@ -0,0 +1,21 @@
+package command
+
+import (
+	"github.com/mitchellh/cli"
+	"github.com/yasuoza/switchbot-ble-go/v2/pkg/switchbot"
+)
+
+// parseAddr normalizes ADDRESS argument. Aliases are resolved to address,
+// and "nearest" is kept as it is to be resolved during scan.
+// If the address is malformed, parseAddr reports it to ui and returns false.
+func parseAddr(ui cli.Ui, s string) (string, bool) {
+	if s == switchbot.Nearest {
+		return s, true
+	}
+	addr, err := switchbot.ResolveAddress(s)
+	if err != nil {
+		ui.Error(err.Error())
+		return "", false
+	}
+	return addr.String(), true
+}
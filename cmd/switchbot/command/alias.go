@@ -0,0 +1,142 @@
+package command
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mitchellh/cli"
+	"github.com/yasuoza/switchbot-ble-go/v2/pkg/switchbot"
+)
+
+// AliasCommand reperesents alias command.
+type AliasCommand struct {
+	UI *cli.BasicUi
+	// Path is path of aliases file.
+	Path string
+}
+
+type aliasCfg struct {
+	Action string
+	Name   string
+	Addr   string
+	Format string
+}
+
+// Run executes parse args and pass args to RunContext.
+func (c *AliasCommand) Run(args []string) int {
+	cfg, parseStatus := c.parseArgs(args)
+
+	if parseStatus != 0 {
+		return parseStatus
+	}
+
+	if c.Path == "" {
+		c.UI.Error("Aliases file is not available, specify it by -aliases")
+		return 1
+	}
+
+	aliases, err := switchbot.LoadAliases(c.Path)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to load aliases: %s", err.Error()))
+		return 1
+	}
+
+	switch cfg.Action {
+	case "list":
+		if cfg.Format == "json" {
+			if err := printAsJSON(aliases); err != nil {
+				c.UI.Error(fmt.Sprintf(`{"error": "Failed to list aliases: %s"}`, err.Error()))
+				return 1
+			}
+		} else {
+			printAliasesAsTable(aliases, c.UI.Writer)
+		}
+		return 0
+	case "set":
+		if err := aliases.Set(cfg.Name, cfg.Addr); err != nil {
+			c.UI.Error(fmt.Sprintf("Failed to set alias: %s", err.Error()))
+			return 1
+		}
+	case "rm":
+		if _, ok := aliases[cfg.Name]; !ok {
+			c.UI.Error(fmt.Sprintf("Alias %q is not found", cfg.Name))
+			return 1
+		}
+		delete(aliases, cfg.Name)
+	}
+
+	if err := aliases.Save(c.Path); err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to save aliases: %s", err.Error()))
+		return 1
+	}
+	return 0
+}
+
+// Help represents help message for alias command.
+func (c *AliasCommand) Help() string {
+	helpText := `
+Usage: switchbot alias [options] [list]
+       switchbot alias set NAME ADDRESS
+       switchbot alias rm NAME
+  Will manage aliases of SwitchBots stored locally.
+  Aliases can be used as ADDRESS of every command.
+  "nearest" can also be used as ADDRESS to select SwitchBot with the strongest RSSI.
+
+Options:
+  -format=table               Output format of list. 'table' and 'json' are available.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis represents synopsis message for alias command.
+func (c *AliasCommand) Synopsis() string {
+	return "Manage aliases of SwitchBots"
+}
+
+func (c *AliasCommand) parseArgs(args []string) (*aliasCfg, int) {
+	cfg := &aliasCfg{}
+	flags := flag.NewFlagSet("alias", flag.ContinueOnError)
+	flags.StringVar(&cfg.Format, "format", "table", "")
+	flags.Usage = func() {
+		c.UI.Info(c.Help())
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return cfg, 127
+	}
+
+	args = flags.Args()
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+	if cfg.Format != "table" && cfg.Format != "json" {
+		flags.Usage()
+		return cfg, 127
+	}
+
+	cfg.Action = args[0]
+	switch {
+	case cfg.Action == "list" && len(args) == 1:
+	case cfg.Action == "set" && len(args) == 3:
+		cfg.Name = args[1]
+		cfg.Addr = args[2]
+	case cfg.Action == "rm" && len(args) == 2:
+		cfg.Name = args[1]
+	default:
+		flags.Usage()
+		return cfg, 127
+	}
+	return cfg, 0
+}
+
+func printAliasesAsTable(aliases switchbot.Aliases, writer io.Writer) {
+	table := newTable(writer)
+	table.SetHeader([]string{"Alias", "Address"})
+	for _, name := range aliases.Names() {
+		table.Append([]string{name, aliases[name].String()})
+	}
+	table.Render()
+}
@@ -0,0 +1,29 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"github.com/yasuoza/switchbot-ble-go/v2/pkg/switchbot"
+)
+
+// retryAndAudit executes f with retry and appends the result to audit if it is not nil.
+// Failure of appending is reported to ui as warning.
+func retryAndAudit(ctx context.Context, ui cli.Ui, audit *switchbot.AuditLog, addr, action string, f func() error, p *switchbot.RetryPolicy) error {
+	start := time.Now()
+	attempts := 0
+	err := p.Do(ctx, func() error {
+		attempts++
+		return f()
+	})
+
+	if audit != nil {
+		e := switchbot.NewAuditEntry(addr, action, switchbot.InitiatorCLI, start, attempts-1, err)
+		if aerr := audit.Append(e); aerr != nil {
+			ui.Warn(fmt.Sprintf("Failed to write audit log: %s", aerr.Error()))
+		}
+	}
+	return err
+}
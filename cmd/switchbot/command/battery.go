@@ -0,0 +1,161 @@
+package command
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"github.com/yasuoza/switchbot-ble-go/v2/pkg/switchbot"
+)
+
+// BatteryCommand reperesents battery command.
+type BatteryCommand struct {
+	UI *cli.BasicUi
+	// Store is battery store opened by -battery-db.
+	Store *switchbot.BoltBatteryStore
+}
+
+type batteryCfg struct {
+	Format string
+	Days   int
+	Low    int
+}
+
+type batteryStatus struct {
+	*switchbot.BatteryStatus
+	Alias string `json:"alias,omitempty"`
+}
+
+// Run executes parse args and pass args to RunContext.
+func (c *BatteryCommand) Run(args []string) int {
+	cfg, parseStatus := c.parseArgs(args)
+
+	if parseStatus != 0 {
+		return parseStatus
+	}
+
+	var errTmpl string
+	if cfg.Format == "json" {
+		errTmpl = `{"error": "Failed to retreive battery history: %s"}`
+	} else {
+		errTmpl = "Failed to retreive battery history: %s"
+	}
+
+	if c.Store == nil {
+		c.UI.Error(fmt.Sprintf(errTmpl, "battery store is not available, specify it by -battery-db"))
+		return 1
+	}
+
+	statuses, err := c.statuses(cfg)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf(errTmpl, err.Error()))
+		return 1
+	}
+
+	if cfg.Format == "json" {
+		if err := printAsJSON(statuses); err != nil {
+			c.UI.Error(fmt.Sprintf(errTmpl, err.Error()))
+			return 1
+		}
+	} else {
+		printBatteryAsTable(statuses, c.UI.Writer)
+	}
+
+	return 0
+}
+
+func (c *BatteryCommand) statuses(cfg *batteryCfg) ([]*batteryStatus, error) {
+	addrs, err := c.Store.Addrs()
+	if err != nil {
+		return nil, err
+	}
+
+	aliases := switchbot.CurrentAliases()
+	since := time.Now().AddDate(0, 0, -cfg.Days)
+	ret := []*batteryStatus{}
+	for _, addr := range addrs {
+		readings, err := c.Store.BatteryReadings(addr, since)
+		if err != nil {
+			return nil, err
+		}
+		s := switchbot.NewBatteryStatus(readings)
+		if s == nil || (cfg.Low > 0 && s.Level > cfg.Low) {
+			continue
+		}
+		alias, _ := aliases.Alias(switchbot.Address(addr))
+		ret = append(ret, &batteryStatus{BatteryStatus: s, Alias: alias})
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Level < ret[j].Level
+	})
+	return ret, nil
+}
+
+// Help represents help message for battery command.
+func (c *BatteryCommand) Help() string {
+	helpText := `
+Usage: switchbot battery [options]
+  Will show battery level of every SwitchBot recorded by info, scan and other commands.
+  Drain rate and days remaining are estimated from readings since the battery
+  was last replaced.
+
+Options:
+  -format=table               Output format. 'table' and 'json' are available.
+  -days=30                    Days of history used to estimate drain rate. (Default 30)
+  -low=0                      Show only SwitchBots whose level is at or below this percent.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis represents synopsis message for battery command.
+func (c *BatteryCommand) Synopsis() string {
+	return "Show battery history of SwitchBots"
+}
+
+func (c *BatteryCommand) parseArgs(args []string) (*batteryCfg, int) {
+	cfg := &batteryCfg{}
+	flags := flag.NewFlagSet("battery", flag.ContinueOnError)
+	flags.StringVar(&cfg.Format, "format", "table", "")
+	flags.IntVar(&cfg.Days, "days", 30, "")
+	flags.IntVar(&cfg.Low, "low", 0, "")
+	flags.Usage = func() {
+		c.UI.Info(c.Help())
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return cfg, 127
+	}
+
+	if len(flags.Args()) != 0 || cfg.Days <= 0 || (cfg.Format != "table" && cfg.Format != "json") {
+		flags.Usage()
+		return cfg, 127
+	}
+	return cfg, 0
+}
+
+func printBatteryAsTable(statuses []*batteryStatus, writer io.Writer) {
+	table := newTable(writer)
+	table.SetHeader([]string{"Address", "Alias", "Model", "Level(%)", "Trend", "Days Remaining", "Last Seen"})
+	for _, s := range statuses {
+		days := "-"
+		if s.DaysRemaining != nil {
+			days = fmt.Sprintf("%.0f", *s.DaysRemaining)
+		}
+		table.Append([]string{
+			s.Addr,
+			s.Alias,
+			s.Model,
+			fmt.Sprintf("%d", s.Level),
+			s.Trend,
+			days,
+			s.LastSeen.Local().Format(time.RFC3339),
+		})
+	}
+	table.Render()
+}
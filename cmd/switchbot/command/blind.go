@@ -0,0 +1,222 @@
+package command
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"github.com/yasuoza/switchbot-ble-go/v2/pkg/switchbot"
+)
+
+// BlindCommand reperesents blind command.
+type BlindCommand struct {
+	UI *cli.BasicUi
+	// Audit is audit log which actions are appended to. Actions are not logged if nil.
+	Audit *switchbot.AuditLog
+}
+
+type blindCfg struct {
+	Action     string
+	Addr       string
+	Value      string
+	Format     string
+	TimeoutSec int
+	WaitResp   bool
+	retryCfg
+}
+
+// Run executes parse args and pass args to RunContext.
+func (c *BlindCommand) Run(args []string) int {
+	cfg, parseStatus := c.parseArgs(args)
+
+	if parseStatus != 0 {
+		return parseStatus
+	}
+
+	if cfg.Action == "info" {
+		return c.runInfo(context.Background(), cfg)
+	}
+
+	if err := c.runWithRetry(context.Background(), cfg); err != nil {
+		msg := fmt.Sprintf("Failed to %s Blind Tilt: %s", cfg.Action, err.Error())
+		c.UI.Error(msg)
+		return 1
+	}
+
+	return 0
+}
+
+// ConnectAndTrigger executes connect and action specified by cfg.
+func (c *BlindCommand) ConnectAndTrigger(ctx context.Context, cfg *blindCfg) error {
+	blind, err := switchbot.ConnectBlindTilt(ctx, cfg.Addr, time.Duration(cfg.TimeoutSec)*time.Second)
+	if err != nil {
+		return err
+	}
+	defer blind.Disconnect()
+
+	switch cfg.Action {
+	case "open":
+		return blind.Open(cfg.WaitResp)
+	case "close-up":
+		return blind.CloseUp(cfg.WaitResp)
+	case "close-down":
+		return blind.CloseDown(cfg.WaitResp)
+	case "stop":
+		return blind.Stop(cfg.WaitResp)
+	case "position":
+		pos, err := strconv.Atoi(cfg.Value)
+		if err != nil {
+			return err
+		}
+		return blind.SetPosition(pos, cfg.WaitResp)
+	}
+	return nil
+}
+
+// Help represents help message for blind command.
+func (c *BlindCommand) Help() string {
+	helpText := `
+Usage: switchbot blind [options] ACTION ADDRESS [VALUE]
+  Will execute ACTION against a Blind Tilt specified by ADDRESS.
+
+Actions:
+  open                        Tilt to open position.
+  close-up                    Tilt to closed up position.
+  close-down                  Tilt to closed down position.
+  position POSITION           Tilt to position between 0(closed down) and 100(closed up).
+  stop                        Stop tilting.
+  info                        Show current state of the Blind Tilt.
+
+Options:
+  -format=table               Output format of info. 'table' and 'json' are available.
+  -timeout=10                 Scan timeout seconds before connection. (Default 10)
+  -max-retry=0                Maximum retry count. (Default 0, or no limit with -retry-max-elapsed)
+  -retry-backoff=BACKOFF      Interval of retries. 'constant', 'exp' and 'jitter' are available.
+                              (Default 'exp' for info and 'constant' for others)
+  -retry-interval=1s          Interval before the first retry. (Default 1s)
+  -retry-max-elapsed=0        Give up retries after the duration such as '30s'. (Default no limit)
+  -wait=true                  Wait success/failure response from Blind Tilt. (Default true)
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis represents synopsis message for blind command.
+func (c *BlindCommand) Synopsis() string {
+	return "Control Blind Tilt or show its state"
+}
+
+func (c *BlindCommand) parseArgs(args []string) (*blindCfg, int) {
+	cfg := &blindCfg{}
+	flags := flag.NewFlagSet("blind", flag.ContinueOnError)
+	flags.StringVar(&cfg.Format, "format", "table", "")
+	flags.IntVar(&cfg.TimeoutSec, "timeout", 10, "")
+	addRetryFlags(flags, &cfg.retryCfg)
+	flags.BoolVar(&cfg.WaitResp, "wait", true, "")
+	flags.Usage = func() {
+		c.UI.Info(c.Help())
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return cfg, 127
+	}
+
+	if !cfg.valid() {
+		flags.Usage()
+		return cfg, 127
+	}
+
+	args = flags.Args()
+	if len(args) < 2 || (cfg.Format != "table" && cfg.Format != "json") {
+		flags.Usage()
+		return cfg, 127
+	}
+
+	var nargs int
+	switch args[0] {
+	case "open", "close-up", "close-down", "stop", "info":
+		nargs = 2
+	case "position":
+		nargs = 3
+	default:
+		flags.Usage()
+		return cfg, 127
+	}
+	if len(args) != nargs {
+		flags.Usage()
+		return cfg, 127
+	}
+
+	cfg.Action = args[0]
+	addr, ok := parseAddr(c.UI, args[1])
+	if !ok {
+		return cfg, 127
+	}
+	cfg.Addr = addr
+	if nargs == 3 {
+		cfg.Value = args[2]
+	}
+	return cfg, 0
+}
+
+func (c *BlindCommand) runInfo(ctx context.Context, cfg *blindCfg) int {
+	var errTmpl string
+	if cfg.Format == "json" {
+		errTmpl = `{"error": "Failed to retreive info from Blind Tilt: %s"}`
+	} else {
+		errTmpl = "Failed to retreive info from Blind Tilt: %s"
+	}
+
+	var info *switchbot.BlindTiltInfo
+	f := func() error {
+		var err error
+		info, err = switchbot.GetBlindTiltInfo(ctx, cfg.Addr, time.Duration(cfg.TimeoutSec)*time.Second)
+		return err
+	}
+	p := cfg.policy(switchbot.BackoffExponential)
+	if err := p.Do(ctx, f); err != nil {
+		msg := fmt.Sprintf(errTmpl, err.Error())
+		c.UI.Error(msg)
+		return 1
+	}
+
+	if cfg.Format == "json" {
+		if err := printAsJSON(info); err != nil {
+			msg := fmt.Sprintf(errTmpl, err.Error())
+			c.UI.Error(msg)
+			return 1
+		}
+	} else {
+		printBlindAsTable(info, c.UI.Writer)
+	}
+
+	return 0
+}
+
+func printBlindAsTable(i *switchbot.BlindTiltInfo, writer io.Writer) {
+	data := []string{
+		fmt.Sprintf("%d", i.Battery),
+		fmt.Sprintf("%d", i.Position),
+		fmt.Sprintf("%v", i.InMotion),
+		fmt.Sprintf("%d", i.LightLevel),
+		fmt.Sprintf("%v", i.Calibrated),
+	}
+
+	table := newTable(writer)
+	table.SetHeader([]string{"Battery(%)", "Position", "InMotion", "LightLevel", "Calibrated"})
+	table.Append(data)
+	table.Render()
+}
+
+func (c *BlindCommand) runWithRetry(ctx context.Context, cfg *blindCfg) error {
+	f := func() error {
+		return c.ConnectAndTrigger(ctx, cfg)
+	}
+	p := cfg.policy(switchbot.BackoffConstant)
+	return retryAndAudit(ctx, c.UI, c.Audit, cfg.Addr, strings.TrimSpace(cfg.Action+" "+cfg.Value), f, p)
+}
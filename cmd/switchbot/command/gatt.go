@@ -0,0 +1,148 @@
+package command
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"github.com/yasuoza/switchbot-ble-go/v2/pkg/switchbot"
+)
+
+// GATTCommand reperesents gatt command.
+type GATTCommand struct {
+	UI *cli.BasicUi
+}
+
+type gattCfg struct {
+	Addr       string
+	Format     string
+	TimeoutSec int
+	retryCfg
+}
+
+// Run executes parse args and pass args to RunContext.
+func (c *GATTCommand) Run(args []string) int {
+	cfg, parseStatus := c.parseArgs(args)
+
+	if parseStatus != 0 {
+		return parseStatus
+	}
+
+	var errTmpl string
+	if cfg.Format == "json" {
+		errTmpl = `{"error": "Failed to discover GATT of SwitchBot: %s"}`
+	} else {
+		errTmpl = "Failed to discover GATT of SwitchBot: %s"
+	}
+
+	ctx := context.Background()
+	var srvcs []*switchbot.GATTService
+	f := func() error {
+		var err error
+		srvcs, err = switchbot.DiscoverGATT(ctx, cfg.Addr, time.Duration(cfg.TimeoutSec)*time.Second)
+		return err
+	}
+	p := cfg.policy(switchbot.BackoffExponential)
+	if err := p.Do(ctx, f); err != nil {
+		msg := fmt.Sprintf(errTmpl, err.Error())
+		c.UI.Error(msg)
+		return 1
+	}
+
+	if cfg.Format == "json" {
+		if err := printAsJSON(srvcs); err != nil {
+			msg := fmt.Sprintf(errTmpl, err.Error())
+			c.UI.Error(msg)
+			return 1
+		}
+	} else {
+		printGATTAsTable(srvcs, c.UI.Writer)
+	}
+
+	return 0
+}
+
+// Help represents help message for gatt command.
+func (c *GATTCommand) Help() string {
+	helpText := `
+Usage: switchbot gatt [options] ADDRESS
+  Will show every GATT service and characteristic of SwitchBot specified by ADDRESS.
+  Values of readable characteristics are shown in hex.
+  Properties of characteristics are shown only on Linux.
+
+Options:
+  -format=table               Output format. 'table' and 'json' are available.
+  -timeout=10                 Scan timeout seconds before connection. (Default 10)
+  -max-retry=0                Maximum retry count. (Default 0, or no limit with -retry-max-elapsed)
+  -retry-backoff=BACKOFF      Interval of retries. 'constant', 'exp' and 'jitter' are available.
+                              (Default 'exp')
+  -retry-interval=1s          Interval before the first retry. (Default 1s)
+  -retry-max-elapsed=0        Give up retries after the duration such as '30s'. (Default no limit)
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis represents synopsis message for gatt command.
+func (c *GATTCommand) Synopsis() string {
+	return "Show GATT services and characteristics of SwitchBot"
+}
+
+func (c *GATTCommand) parseArgs(args []string) (*gattCfg, int) {
+	cfg := &gattCfg{}
+	flags := flag.NewFlagSet("gatt", flag.ContinueOnError)
+	flags.StringVar(&cfg.Format, "format", "table", "")
+	flags.IntVar(&cfg.TimeoutSec, "timeout", 10, "")
+	addRetryFlags(flags, &cfg.retryCfg)
+	flags.Usage = func() {
+		c.UI.Info(c.Help())
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return cfg, 127
+	}
+
+	if !cfg.valid() {
+		flags.Usage()
+		return cfg, 127
+	}
+
+	args = flags.Args()
+	if len(args) != 1 || (cfg.Format != "table" && cfg.Format != "json") {
+		flags.Usage()
+		return cfg, 127
+	}
+
+	addr, ok := parseAddr(c.UI, args[0])
+	if !ok {
+		return cfg, 127
+	}
+	cfg.Addr = addr
+	return cfg, 0
+}
+
+func printGATTAsTable(srvcs []*switchbot.GATTService, writer io.Writer) {
+	table := newTable(writer)
+	table.SetHeader([]string{"Service", "Characteristic", "Properties", "Value"})
+	for _, s := range srvcs {
+		if len(s.Characteristics) == 0 {
+			table.Append([]string{s.UUID, "-", "-", "-"})
+		}
+		for _, ch := range s.Characteristics {
+			props := strings.Join(ch.Properties, ",")
+			if props == "" {
+				props = "-"
+			}
+			value := "-"
+			if ch.Value != nil {
+				value = fmt.Sprintf("%x", []byte(ch.Value))
+			}
+			table.Append([]string{s.UUID, ch.UUID, props, value})
+		}
+	}
+	table.Render()
+}
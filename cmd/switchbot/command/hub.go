@@ -0,0 +1,142 @@
+package command
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"github.com/yasuoza/switchbot-ble-go/v2/pkg/switchbot"
+)
+
+// HubCommand reperesents hub command.
+type HubCommand struct {
+	UI *cli.BasicUi
+}
+
+type hubCfg struct {
+	Addr       string
+	Format     string
+	TimeoutSec int
+	retryCfg
+}
+
+// Run executes parse args and pass args to RunContext.
+func (c *HubCommand) Run(args []string) int {
+	cfg, parseStatus := c.parseArgs(args)
+
+	if parseStatus != 0 {
+		return parseStatus
+	}
+
+	var errTmpl string
+	if cfg.Format == "json" {
+		errTmpl = `{"error": "Failed to retreive readings from Hub: %s"}`
+	} else {
+		errTmpl = "Failed to retreive readings from Hub: %s"
+	}
+
+	info, err := c.runWithRetry(context.Background(), cfg)
+	if err != nil {
+		msg := fmt.Sprintf(errTmpl, err.Error())
+		c.UI.Error(msg)
+		return 1
+	}
+
+	if cfg.Format == "json" {
+		err := printAsJSON(info)
+		if err != nil {
+			msg := fmt.Sprintf(errTmpl, err.Error())
+			c.UI.Error(msg)
+			return 1
+		}
+	} else {
+		printHubAsTable(info, c.UI.Writer)
+	}
+
+	return 0
+}
+
+// Help represents help message for hub command.
+func (c *HubCommand) Help() string {
+	helpText := `
+Usage: switchbot hub [options] ADDRESS
+  Will retreive temperature, humidity and light level from a Hub 2 specified by ADDRESS.
+
+Options:
+  -format=table               Output format. 'table' and 'json' are available.
+  -max-retry=0                Maximum retry count. (Default 0, or no limit with -retry-max-elapsed)
+  -retry-backoff=BACKOFF      Interval of retries. 'constant', 'exp' and 'jitter' are available.
+                              (Default 'exp')
+  -retry-interval=1s          Interval before the first retry. (Default 1s)
+  -retry-max-elapsed=0        Give up retries after the duration such as '30s'. (Default no limit)
+  -timeout=10                 Scan timeout seconds. (Default 10)
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis represents synopsis message for hub command.
+func (c *HubCommand) Synopsis() string {
+	return "Show sensor readings of Hub 2"
+}
+
+func (c *HubCommand) parseArgs(args []string) (*hubCfg, int) {
+	cfg := &hubCfg{}
+	flags := flag.NewFlagSet("hub", flag.ContinueOnError)
+	flags.StringVar(&cfg.Format, "format", "table", "")
+	addRetryFlags(flags, &cfg.retryCfg)
+	flags.IntVar(&cfg.TimeoutSec, "timeout", 10, "")
+	flags.Usage = func() {
+		c.UI.Info(c.Help())
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return cfg, 127
+	}
+
+	if !cfg.valid() {
+		flags.Usage()
+		return cfg, 127
+	}
+
+	args = flags.Args()
+	if len(args) != 1 || (cfg.Format != "table" && cfg.Format != "json") {
+		flags.Usage()
+		return cfg, 127
+	}
+
+	addr, ok := parseAddr(c.UI, args[0])
+	if !ok {
+		return cfg, 127
+	}
+	cfg.Addr = addr
+	return cfg, 0
+}
+
+func printHubAsTable(i *switchbot.HubInfo, writer io.Writer) {
+	data := []string{
+		fmt.Sprintf("%0.1f", i.Temperature),
+		fmt.Sprintf("%d", i.Humidity),
+		fmt.Sprintf("%d", i.LightLevel),
+	}
+
+	table := newTable(writer)
+	table.SetHeader([]string{"Temperature(C)", "Humidity(%)", "LightLevel"})
+	table.Append(data)
+	table.Render()
+}
+
+func (c *HubCommand) runWithRetry(ctx context.Context, cfg *hubCfg) (*switchbot.HubInfo, error) {
+	var info *switchbot.HubInfo
+	f := func() error {
+		var err error
+		info, err = switchbot.GetHubInfo(ctx, cfg.Addr, time.Duration(cfg.TimeoutSec)*time.Second)
+		return err
+	}
+	p := cfg.policy(switchbot.BackoffExponential)
+	return info, p.Do(ctx, f)
+}
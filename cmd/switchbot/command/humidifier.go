@@ -0,0 +1,232 @@
+package command
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"github.com/yasuoza/switchbot-ble-go/v2/pkg/switchbot"
+)
+
+// HumidifierCommand reperesents humidifier command.
+type HumidifierCommand struct {
+	UI *cli.BasicUi
+	// Audit is audit log which actions are appended to. Actions are not logged if nil.
+	Audit *switchbot.AuditLog
+}
+
+type humidifierCfg struct {
+	Action     string
+	Addr       string
+	Value      string
+	Format     string
+	TimeoutSec int
+	WaitResp   bool
+	retryCfg
+}
+
+// Run executes parse args and pass args to RunContext.
+func (c *HumidifierCommand) Run(args []string) int {
+	cfg, parseStatus := c.parseArgs(args)
+
+	if parseStatus != 0 {
+		return parseStatus
+	}
+
+	if cfg.Action == "info" {
+		return c.runInfo(context.Background(), cfg)
+	}
+
+	if err := c.runWithRetry(context.Background(), cfg); err != nil {
+		msg := fmt.Sprintf("Failed to %s Humidifier: %s", cfg.Action, err.Error())
+		c.UI.Error(msg)
+		return 1
+	}
+
+	return 0
+}
+
+// ConnectAndTrigger executes connect and action specified by cfg.
+func (c *HumidifierCommand) ConnectAndTrigger(ctx context.Context, cfg *humidifierCfg) error {
+	humi, err := switchbot.ConnectHumidifier(ctx, cfg.Addr, time.Duration(cfg.TimeoutSec)*time.Second)
+	if err != nil {
+		return err
+	}
+	defer humi.Disconnect()
+
+	switch cfg.Action {
+	case "on":
+		return humi.On(cfg.WaitResp)
+	case "off":
+		return humi.Off(cfg.WaitResp)
+	case "auto":
+		return humi.SetAuto(cfg.WaitResp)
+	case "level":
+		level, err := strconv.Atoi(cfg.Value)
+		if err != nil {
+			return err
+		}
+		return humi.SetLevel(level, cfg.WaitResp)
+	}
+	return nil
+}
+
+// Help represents help message for humidifier command.
+func (c *HumidifierCommand) Help() string {
+	helpText := `
+Usage: switchbot humidifier [options] ACTION ADDRESS [VALUE]
+  Will execute ACTION against a Humidifier specified by ADDRESS.
+
+Actions:
+  on                          Turn on the Humidifier in auto mode.
+  off                         Turn off the Humidifier.
+  auto                        Set auto mode.
+  level LEVEL                 Set target humidity level between 1 and 100.
+  info                        Show current state of the Humidifier.
+
+Options:
+  -format=table               Output format of info. 'table' and 'json' are available.
+  -timeout=10                 Scan timeout seconds before connection. (Default 10)
+  -max-retry=0                Maximum retry count. (Default 0, or no limit with -retry-max-elapsed)
+  -retry-backoff=BACKOFF      Interval of retries. 'constant', 'exp' and 'jitter' are available.
+                              (Default 'exp' for info and 'constant' for others)
+  -retry-interval=1s          Interval before the first retry. (Default 1s)
+  -retry-max-elapsed=0        Give up retries after the duration such as '30s'. (Default no limit)
+  -wait=true                  Wait success/failure response from Humidifier. (Default true)
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis represents synopsis message for humidifier command.
+func (c *HumidifierCommand) Synopsis() string {
+	return "Control Humidifier or show its state"
+}
+
+func (c *HumidifierCommand) parseArgs(args []string) (*humidifierCfg, int) {
+	cfg := &humidifierCfg{}
+	flags := flag.NewFlagSet("humidifier", flag.ContinueOnError)
+	flags.StringVar(&cfg.Format, "format", "table", "")
+	flags.IntVar(&cfg.TimeoutSec, "timeout", 10, "")
+	addRetryFlags(flags, &cfg.retryCfg)
+	flags.BoolVar(&cfg.WaitResp, "wait", true, "")
+	flags.Usage = func() {
+		c.UI.Info(c.Help())
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return cfg, 127
+	}
+
+	if !cfg.valid() {
+		flags.Usage()
+		return cfg, 127
+	}
+
+	args = flags.Args()
+	if len(args) < 2 || (cfg.Format != "table" && cfg.Format != "json") {
+		flags.Usage()
+		return cfg, 127
+	}
+
+	var nargs int
+	switch args[0] {
+	case "on", "off", "auto", "info":
+		nargs = 2
+	case "level":
+		nargs = 3
+	default:
+		flags.Usage()
+		return cfg, 127
+	}
+	if len(args) != nargs {
+		flags.Usage()
+		return cfg, 127
+	}
+
+	cfg.Action = args[0]
+	addr, ok := parseAddr(c.UI, args[1])
+	if !ok {
+		return cfg, 127
+	}
+	cfg.Addr = addr
+	if nargs == 3 {
+		cfg.Value = args[2]
+	}
+	return cfg, 0
+}
+
+func (c *HumidifierCommand) runInfo(ctx context.Context, cfg *humidifierCfg) int {
+	var errTmpl string
+	if cfg.Format == "json" {
+		errTmpl = `{"error": "Failed to retreive info from Humidifier: %s"}`
+	} else {
+		errTmpl = "Failed to retreive info from Humidifier: %s"
+	}
+
+	var info *switchbot.HumidifierInfo
+	f := func() error {
+		var err error
+		info, err = switchbot.GetHumidifierInfo(ctx, cfg.Addr, time.Duration(cfg.TimeoutSec)*time.Second)
+		return err
+	}
+	p := cfg.policy(switchbot.BackoffExponential)
+	if err := p.Do(ctx, f); err != nil {
+		msg := fmt.Sprintf(errTmpl, err.Error())
+		c.UI.Error(msg)
+		return 1
+	}
+
+	if cfg.Format == "json" {
+		if err := printAsJSON(info); err != nil {
+			msg := fmt.Sprintf(errTmpl, err.Error())
+			c.UI.Error(msg)
+			return 1
+		}
+	} else {
+		printHumidifierAsTable(info, c.UI.Writer)
+	}
+
+	return 0
+}
+
+func printHumidifierAsTable(i *switchbot.HumidifierInfo, writer io.Writer) {
+	var power string
+	if i.On {
+		power = "on"
+	} else {
+		power = "off"
+	}
+
+	var mode string
+	if i.Auto {
+		mode = "auto"
+	} else {
+		mode = "manual"
+	}
+
+	data := []string{
+		power,
+		mode,
+		fmt.Sprintf("%d", i.Level),
+		fmt.Sprintf("%v", i.WaterEmpty),
+	}
+
+	table := newTable(writer)
+	table.SetHeader([]string{"Power", "Mode", "Level(%)", "WaterEmpty"})
+	table.Append(data)
+	table.Render()
+}
+
+func (c *HumidifierCommand) runWithRetry(ctx context.Context, cfg *humidifierCfg) error {
+	f := func() error {
+		return c.ConnectAndTrigger(ctx, cfg)
+	}
+	p := cfg.policy(switchbot.BackoffConstant)
+	return retryAndAudit(ctx, c.UI, c.Audit, cfg.Addr, strings.TrimSpace(cfg.Action+" "+cfg.Value), f, p)
+}
@@ -9,7 +9,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/cenkalti/backoff/v4"
 	"github.com/mitchellh/cli"
 	"github.com/olekukonko/tablewriter"
 	"github.com/yasuoza/switchbot-ble-go/v2/pkg/switchbot"
@@ -23,8 +22,8 @@ type InfoCommand struct {
 type infoCfg struct {
 	Addr       string
 	Format     string
-	MaxRetry   int
 	TimeoutSec int
+	retryCfg
 }
 
 // Run executes parse args and pass args to RunContext.
@@ -87,8 +86,12 @@ Usage: switchbot info [options] ADDRESS
 
 Options:
   -format=table               Output format. 'table' and 'json' are available.
-  -max-retry=0                Maximum retry count. (Default 0)
-  -timeout=10                 Connection timeout seconds. (Default 10)
+  -max-retry=0                Maximum retry count. (Default 0, or no limit with -retry-max-elapsed)
+  -retry-backoff=BACKOFF      Interval of retries. 'constant', 'exp' and 'jitter' are available.
+                              (Default 'exp')
+  -retry-interval=1s          Interval before the first retry. (Default 1s)
+  -retry-max-elapsed=0        Give up retries after the duration such as '30s'. (Default no limit)
+  -timeout=10                 Scan timeout seconds before connection. (Default 10)
 `
 
 	return strings.TrimSpace(helpText)
@@ -103,7 +106,7 @@ func (c *InfoCommand) parseArgs(args []string) (*infoCfg, int) {
 	cfg := &infoCfg{}
 	flags := flag.NewFlagSet("info", flag.ContinueOnError)
 	flags.StringVar(&cfg.Format, "format", "table", "")
-	flags.IntVar(&cfg.MaxRetry, "max-retry", 0, "")
+	addRetryFlags(flags, &cfg.retryCfg)
 	flags.IntVar(&cfg.TimeoutSec, "timeout", 10, "")
 	flags.Usage = func() {
 		c.UI.Info(c.Help())
@@ -113,18 +116,27 @@ func (c *InfoCommand) parseArgs(args []string) (*infoCfg, int) {
 		return cfg, 127
 	}
 
+	if !cfg.valid() {
+		flags.Usage()
+		return cfg, 127
+	}
+
 	args = flags.Args()
 	if len(args) != 1 || (cfg.Format != "table" && cfg.Format != "json") {
 		flags.Usage()
 		return cfg, 127
 	}
 
-	cfg.Addr = args[0]
+	addr, ok := parseAddr(c.UI, args[0])
+	if !ok {
+		return cfg, 127
+	}
+	cfg.Addr = addr
 	return cfg, 0
 }
 
-func printAsJSON(i *switchbot.BotInfo) error {
-	data, err := json.MarshalIndent(i, "", "  ")
+func printAsJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		return err
 	}
@@ -149,8 +161,14 @@ func printAsTable(i *switchbot.BotInfo, writer io.Writer) {
 		fmt.Sprintf("%d", i.HoldSec),
 	}
 
-	table := tablewriter.NewWriter(writer)
+	table := newTable(writer)
 	table.SetHeader([]string{"Battery(%)", "Firmware", "Timers", "Mode", "Inverse", "Hold(sec)"})
+	table.Append(data)
+	table.Render()
+}
+
+func newTable(writer io.Writer) *tablewriter.Table {
+	table := tablewriter.NewWriter(writer)
 	table.SetAutoWrapText(false)
 	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
 	table.SetCenterSeparator("")
@@ -160,8 +178,7 @@ func printAsTable(i *switchbot.BotInfo, writer io.Writer) {
 	table.SetBorder(false)
 	table.SetTablePadding("\t")
 	table.SetNoWhiteSpace(true)
-	table.Append(data)
-	table.Render()
+	return table
 }
 
 func (c *InfoCommand) runWithRetry(ctx context.Context, cfg *infoCfg) (*switchbot.BotInfo, error) {
@@ -171,7 +188,6 @@ func (c *InfoCommand) runWithRetry(ctx context.Context, cfg *infoCfg) (*switchbo
 		info, err = c.ConnectAndGetInfo(ctx, cfg)
 		return err
 	}
-	bo := backoff.NewExponentialBackOff()
-	bw := backoff.WithMaxRetries(bo, uint64(cfg.MaxRetry))
-	return info, backoff.Retry(f, bw)
+	p := cfg.policy(switchbot.BackoffExponential)
+	return info, p.Do(ctx, f)
 }
@@ -0,0 +1,280 @@
+package command
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"github.com/yasuoza/switchbot-ble-go/v2/pkg/switchbot"
+)
+
+// LightCommand reperesents light command.
+type LightCommand struct {
+	UI *cli.BasicUi
+	// Audit is audit log which actions are appended to. Actions are not logged if nil.
+	Audit *switchbot.AuditLog
+}
+
+type lightCfg struct {
+	Action     string
+	Addr       string
+	Value      string
+	Type       string
+	Brightness int
+	Format     string
+	TimeoutSec int
+	WaitResp   bool
+	retryCfg
+}
+
+// lightDevice represents commands shared by Color Bulb and Strip Light.
+type lightDevice interface {
+	On(wait bool) error
+	Off(wait bool) error
+	SetBrightness(level int, wait bool) error
+	SetRGB(brightness int, r, g, b uint8, wait bool) error
+	SetEffect(effect int, wait bool) error
+	Disconnect() error
+}
+
+// Run executes parse args and pass args to RunContext.
+func (c *LightCommand) Run(args []string) int {
+	cfg, parseStatus := c.parseArgs(args)
+
+	if parseStatus != 0 {
+		return parseStatus
+	}
+
+	if cfg.Action == "info" {
+		return c.runInfo(context.Background(), cfg)
+	}
+
+	if err := c.runWithRetry(context.Background(), cfg); err != nil {
+		msg := fmt.Sprintf("Failed to %s light: %s", cfg.Action, err.Error())
+		c.UI.Error(msg)
+		return 1
+	}
+
+	return 0
+}
+
+// ConnectAndTrigger executes connect and action specified by cfg.
+func (c *LightCommand) ConnectAndTrigger(ctx context.Context, cfg *lightCfg) error {
+	timeout := time.Duration(cfg.TimeoutSec) * time.Second
+
+	var dev lightDevice
+	var bulb *switchbot.Bulb
+	if cfg.Type == "strip" {
+		if cfg.Action == "temp" {
+			return errors.New("strip light does not support color temperature")
+		}
+		strip, err := switchbot.ConnectStripLight(ctx, cfg.Addr, timeout)
+		if err != nil {
+			return err
+		}
+		dev = strip
+	} else {
+		var err error
+		bulb, err = switchbot.ConnectBulb(ctx, cfg.Addr, timeout)
+		if err != nil {
+			return err
+		}
+		dev = bulb
+	}
+	defer dev.Disconnect()
+
+	switch cfg.Action {
+	case "on":
+		return dev.On(cfg.WaitResp)
+	case "off":
+		return dev.Off(cfg.WaitResp)
+	case "brightness":
+		level, err := strconv.Atoi(cfg.Value)
+		if err != nil {
+			return err
+		}
+		return dev.SetBrightness(level, cfg.WaitResp)
+	case "color":
+		rgb, err := hex.DecodeString(strings.TrimPrefix(cfg.Value, "#"))
+		if err != nil || len(rgb) != 3 {
+			return fmt.Errorf("color must be RRGGBB hex string, got %q", cfg.Value)
+		}
+		return dev.SetRGB(cfg.Brightness, rgb[0], rgb[1], rgb[2], cfg.WaitResp)
+	case "temp":
+		kelvin, err := strconv.Atoi(cfg.Value)
+		if err != nil {
+			return err
+		}
+		return bulb.SetColorTemp(cfg.Brightness, kelvin, cfg.WaitResp)
+	case "effect":
+		effect, err := strconv.Atoi(cfg.Value)
+		if err != nil {
+			return err
+		}
+		return dev.SetEffect(effect, cfg.WaitResp)
+	}
+	return nil
+}
+
+// Help represents help message for light command.
+func (c *LightCommand) Help() string {
+	helpText := `
+Usage: switchbot light [options] ACTION ADDRESS [VALUE]
+  Will execute ACTION against a Color Bulb or Strip Light specified by ADDRESS.
+
+Actions:
+  on                          Turn on the light.
+  off                         Turn off the light.
+  brightness LEVEL            Set brightness between 1 and 100.
+  color RRGGBB                Set RGB color.
+  temp KELVIN                 Set color temperature between 2700 and 6500. Color Bulb only.
+  effect NUMBER               Start built-in effect.
+  info                        Show current state of the light.
+
+Options:
+  -type=bulb                  Device type. 'bulb' and 'strip' are available. (Default bulb)
+  -brightness=100             Brightness between 1 and 100 set with color and temp. (Default 100)
+  -format=table               Output format of info. 'table' and 'json' are available.
+  -timeout=10                 Scan timeout seconds before connection. (Default 10)
+  -max-retry=0                Maximum retry count. (Default 0, or no limit with -retry-max-elapsed)
+  -retry-backoff=BACKOFF      Interval of retries. 'constant', 'exp' and 'jitter' are available.
+                              (Default 'exp' for info and 'constant' for others)
+  -retry-interval=1s          Interval before the first retry. (Default 1s)
+  -retry-max-elapsed=0        Give up retries after the duration such as '30s'. (Default no limit)
+  -wait=true                  Wait success/failure response from the light. (Default true)
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis represents synopsis message for light command.
+func (c *LightCommand) Synopsis() string {
+	return "Control Color Bulb or Strip Light"
+}
+
+func (c *LightCommand) parseArgs(args []string) (*lightCfg, int) {
+	cfg := &lightCfg{}
+	flags := flag.NewFlagSet("light", flag.ContinueOnError)
+	flags.StringVar(&cfg.Type, "type", "bulb", "")
+	flags.IntVar(&cfg.Brightness, "brightness", 100, "")
+	flags.StringVar(&cfg.Format, "format", "table", "")
+	flags.IntVar(&cfg.TimeoutSec, "timeout", 10, "")
+	addRetryFlags(flags, &cfg.retryCfg)
+	flags.BoolVar(&cfg.WaitResp, "wait", true, "")
+	flags.Usage = func() {
+		c.UI.Info(c.Help())
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return cfg, 127
+	}
+
+	if !cfg.valid() {
+		flags.Usage()
+		return cfg, 127
+	}
+
+	args = flags.Args()
+	if len(args) < 2 ||
+		(cfg.Type != "bulb" && cfg.Type != "strip") ||
+		(cfg.Format != "table" && cfg.Format != "json") {
+		flags.Usage()
+		return cfg, 127
+	}
+
+	var nargs int
+	switch args[0] {
+	case "on", "off", "info":
+		nargs = 2
+	case "brightness", "color", "temp", "effect":
+		nargs = 3
+	default:
+		flags.Usage()
+		return cfg, 127
+	}
+	if len(args) != nargs {
+		flags.Usage()
+		return cfg, 127
+	}
+
+	cfg.Action = args[0]
+	addr, ok := parseAddr(c.UI, args[1])
+	if !ok {
+		return cfg, 127
+	}
+	cfg.Addr = addr
+	if nargs == 3 {
+		cfg.Value = args[2]
+	}
+	return cfg, 0
+}
+
+func (c *LightCommand) runInfo(ctx context.Context, cfg *lightCfg) int {
+	var errTmpl string
+	if cfg.Format == "json" {
+		errTmpl = `{"error": "Failed to retreive info from light: %s"}`
+	} else {
+		errTmpl = "Failed to retreive info from light: %s"
+	}
+
+	var info *switchbot.LightInfo
+	f := func() error {
+		var err error
+		info, err = switchbot.GetLightInfo(ctx, cfg.Addr, time.Duration(cfg.TimeoutSec)*time.Second)
+		return err
+	}
+	p := cfg.policy(switchbot.BackoffExponential)
+	if err := p.Do(ctx, f); err != nil {
+		msg := fmt.Sprintf(errTmpl, err.Error())
+		c.UI.Error(msg)
+		return 1
+	}
+
+	if cfg.Format == "json" {
+		if err := printAsJSON(info); err != nil {
+			msg := fmt.Sprintf(errTmpl, err.Error())
+			c.UI.Error(msg)
+			return 1
+		}
+	} else {
+		printLightAsTable(info, c.UI.Writer)
+	}
+
+	return 0
+}
+
+func printLightAsTable(i *switchbot.LightInfo, writer io.Writer) {
+	var power string
+	if i.On {
+		power = "on"
+	} else {
+		power = "off"
+	}
+
+	data := []string{
+		power,
+		fmt.Sprintf("%d", i.Brightness),
+		i.ColorMode.String(),
+		fmt.Sprintf("%d", i.Speed),
+	}
+
+	table := newTable(writer)
+	table.SetHeader([]string{"Power", "Brightness(%)", "Mode", "Speed"})
+	table.Append(data)
+	table.Render()
+}
+
+func (c *LightCommand) runWithRetry(ctx context.Context, cfg *lightCfg) error {
+	f := func() error {
+		return c.ConnectAndTrigger(ctx, cfg)
+	}
+	p := cfg.policy(switchbot.BackoffConstant)
+	return retryAndAudit(ctx, c.UI, c.Audit, cfg.Addr, strings.TrimSpace(cfg.Action+" "+cfg.Value), f, p)
+}
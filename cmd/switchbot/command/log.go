@@ -0,0 +1,170 @@
+package command
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"github.com/yasuoza/switchbot-ble-go/v2/pkg/switchbot"
+)
+
+// LogCommand reperesents log command.
+type LogCommand struct {
+	UI *cli.BasicUi
+	// Path is path of audit log.
+	Path string
+}
+
+type logCfg struct {
+	Device string
+	Since  string
+	Until  string
+	Format string
+}
+
+// Run executes parse args and pass args to RunContext.
+func (c *LogCommand) Run(args []string) int {
+	cfg, parseStatus := c.parseArgs(args)
+
+	if parseStatus != 0 {
+		return parseStatus
+	}
+
+	var errTmpl string
+	if cfg.Format == "json" {
+		errTmpl = `{"error": "Failed to read audit log: %s"}`
+	} else {
+		errTmpl = "Failed to read audit log: %s"
+	}
+
+	filter, err := newAuditFilter(cfg, time.Now())
+	if err != nil {
+		c.UI.Error(fmt.Sprintf(errTmpl, err.Error()))
+		return 127
+	}
+
+	if c.Path == "" {
+		c.UI.Error(fmt.Sprintf(errTmpl, "audit log is not available, specify it by -audit-log"))
+		return 1
+	}
+
+	entries := []*switchbot.AuditEntry{}
+	f, err := os.Open(c.Path)
+	if err != nil && !os.IsNotExist(err) {
+		c.UI.Error(fmt.Sprintf(errTmpl, err.Error()))
+		return 1
+	}
+	if err == nil {
+		defer f.Close()
+		if entries, err = switchbot.ReadAuditLog(f, filter); err != nil {
+			c.UI.Error(fmt.Sprintf(errTmpl, err.Error()))
+			return 1
+		}
+	}
+
+	if cfg.Format == "json" {
+		if err := printAsJSON(entries); err != nil {
+			c.UI.Error(fmt.Sprintf(errTmpl, err.Error()))
+			return 1
+		}
+	} else {
+		printAuditLogAsTable(entries, c.UI.Writer)
+	}
+
+	return 0
+}
+
+// Help represents help message for log command.
+func (c *LogCommand) Help() string {
+	helpText := `
+Usage: switchbot log [options]
+  Will show actions executed by press, up, down and other commands.
+  Actions are recorded to the audit log specified by -audit-log global option.
+
+Options:
+  -device=ADDRESS             Show only actions against ADDRESS or alias.
+  -since=24h                  Show actions since the time. Duration before now such as
+                              '12h' or RFC3339 time such as '2026-01-02T22:00:00+09:00'.
+  -until=TIME                 Show actions before the time. Same format as -since.
+  -format=table               Output format. 'table' and 'json' are available.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis represents synopsis message for log command.
+func (c *LogCommand) Synopsis() string {
+	return "Show audit log of actions"
+}
+
+func (c *LogCommand) parseArgs(args []string) (*logCfg, int) {
+	cfg := &logCfg{}
+	flags := flag.NewFlagSet("log", flag.ContinueOnError)
+	flags.StringVar(&cfg.Device, "device", "", "")
+	flags.StringVar(&cfg.Since, "since", "24h", "")
+	flags.StringVar(&cfg.Until, "until", "", "")
+	flags.StringVar(&cfg.Format, "format", "table", "")
+	flags.Usage = func() {
+		c.UI.Info(c.Help())
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return cfg, 127
+	}
+
+	if len(flags.Args()) != 0 || (cfg.Format != "table" && cfg.Format != "json") {
+		flags.Usage()
+		return cfg, 127
+	}
+	return cfg, 0
+}
+
+func newAuditFilter(cfg *logCfg, now time.Time) (*switchbot.AuditFilter, error) {
+	since, err := parseLogTime(cfg.Since, now)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -since: %w", err)
+	}
+	until, err := parseLogTime(cfg.Until, now)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -until: %w", err)
+	}
+	return &switchbot.AuditFilter{Addr: cfg.Device, Since: since, Until: until}, nil
+}
+
+// parseLogTime parses s as duration before now or RFC3339 time.
+// Empty s is parsed as zero time.
+func parseLogTime(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+func printAuditLogAsTable(entries []*switchbot.AuditEntry, writer io.Writer) {
+	table := newTable(writer)
+	table.SetHeader([]string{"Time", "Address", "Alias", "Action", "Initiator", "Outcome", "Latency(ms)", "Retries"})
+	for _, e := range entries {
+		outcome := e.Outcome
+		if e.Error != "" {
+			outcome = fmt.Sprintf("%s: %s", e.Outcome, e.Error)
+		}
+		table.Append([]string{
+			e.Time.Local().Format(time.RFC3339),
+			e.Addr,
+			e.Alias,
+			e.Action,
+			e.Initiator,
+			outcome,
+			fmt.Sprintf("%d", e.LatencyMS),
+			fmt.Sprintf("%d", e.Retries),
+		})
+	}
+	table.Render()
+}
@@ -0,0 +1,156 @@
+package command
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"github.com/yasuoza/switchbot-ble-go/v2/pkg/switchbot"
+)
+
+// OffCommand reperesents off command.
+type OffCommand struct {
+	UI *cli.BasicUi
+	// Audit is audit log which actions are appended to. Actions are not logged if nil.
+	Audit *switchbot.AuditLog
+}
+
+type offCfg struct {
+	Addr       string
+	TimeoutSec int
+	WaitResp   bool
+	IfNeeded   bool
+	Verify     bool
+	VerifySec  int
+	retryCfg
+}
+
+// Run executes parse args and pass args to RunContext.
+func (c *OffCommand) Run(args []string) int {
+	cfg, parseStatus := c.parseArgs(args)
+
+	if parseStatus != 0 {
+		return parseStatus
+	}
+
+	changed, err := c.runWithRetry(context.Background(), cfg)
+	if err != nil {
+		msg := fmt.Sprintf("Failed to turn off SwitchBot: %s", err.Error())
+		c.UI.Error(msg)
+		return 1
+	}
+
+	if cfg.IfNeeded {
+		if changed {
+			c.UI.Output("changed")
+		} else {
+			c.UI.Output("unchanged")
+		}
+	}
+	return 0
+}
+
+// ConnectAndOff executes connect and off.
+// It reports whether off command was triggered.
+func (c *OffCommand) ConnectAndOff(ctx context.Context, cfg *offCfg) (bool, error) {
+	if cfg.Verify {
+		opts := &switchbot.VerifyOptions{
+			ConnectTimeout: time.Duration(cfg.TimeoutSec) * time.Second,
+			Window:         time.Duration(cfg.VerifySec) * time.Second,
+			IfNeeded:       cfg.IfNeeded,
+		}
+		return switchbot.SwitchVerified(ctx, cfg.Addr, false, opts)
+	}
+
+	bot, err := switchbot.Connect(ctx, cfg.Addr, time.Duration(cfg.TimeoutSec)*time.Second)
+	if err != nil {
+		return false, err
+	}
+	defer bot.Disconnect()
+
+	if cfg.IfNeeded {
+		return bot.EnsureOff(cfg.WaitResp)
+	}
+	if err := bot.Off(cfg.WaitResp); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Help represents help message for off command.
+func (c *OffCommand) Help() string {
+	helpText := `
+Usage: switchbot off [options] ADDRESS
+  Will execute off command against a SwitchBot in on/off mode specified by ADDRESS.
+
+Options:
+  -timeout=10                 Scan timeout seconds before connection. (Default 10)
+  -max-retry=0                Maximum retry count. (Default 0, or no limit with -retry-max-elapsed)
+  -retry-backoff=BACKOFF      Interval of retries. 'constant', 'exp' and 'jitter' are available.
+                              (Default 'constant')
+  -retry-interval=1s          Interval before the first retry. (Default 1s)
+  -retry-max-elapsed=0        Give up retries after the duration such as '30s'. (Default no limit)
+  -wait=true                  Wait success/failure response from SwitchBot. (Default true)
+  -if-needed=false            Skip off command if SwitchBot advertises it is already off,
+                              and print whether state was changed. (Default false)
+  -verify=false               Confirm SwitchBot advertises it is off after the command,
+                              and retry the command up to 3 times if not. (Default false)
+  -verify-window=5            Seconds to wait for the advertisement of each command. (Default 5)
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis represents synopsis message for off command.
+func (c *OffCommand) Synopsis() string {
+	return "Trigger off command"
+}
+
+func (c *OffCommand) parseArgs(args []string) (*offCfg, int) {
+	cfg := &offCfg{}
+	flags := flag.NewFlagSet("off", flag.ContinueOnError)
+	flags.IntVar(&cfg.TimeoutSec, "timeout", 10, "")
+	addRetryFlags(flags, &cfg.retryCfg)
+	flags.BoolVar(&cfg.WaitResp, "wait", true, "")
+	flags.BoolVar(&cfg.IfNeeded, "if-needed", false, "")
+	flags.BoolVar(&cfg.Verify, "verify", false, "")
+	flags.IntVar(&cfg.VerifySec, "verify-window", 5, "")
+	flags.Usage = func() {
+		c.UI.Info(c.Help())
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return cfg, 127
+	}
+
+	if !cfg.valid() {
+		flags.Usage()
+		return cfg, 127
+	}
+
+	args = flags.Args()
+	if len(args) != 1 {
+		flags.Usage()
+		return cfg, 127
+	}
+	addr, ok := parseAddr(c.UI, args[0])
+	if !ok {
+		return cfg, 127
+	}
+	cfg.Addr = addr
+	return cfg, 0
+}
+
+func (c *OffCommand) runWithRetry(ctx context.Context, cfg *offCfg) (bool, error) {
+	var changed bool
+	f := func() error {
+		var err error
+		changed, err = c.ConnectAndOff(ctx, cfg)
+		return err
+	}
+	p := cfg.policy(switchbot.BackoffConstant)
+	return changed, retryAndAudit(ctx, c.UI, c.Audit, cfg.Addr, "off", f, p)
+}
@@ -0,0 +1,156 @@
+package command
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"github.com/yasuoza/switchbot-ble-go/v2/pkg/switchbot"
+)
+
+// OnCommand reperesents on command.
+type OnCommand struct {
+	UI *cli.BasicUi
+	// Audit is audit log which actions are appended to. Actions are not logged if nil.
+	Audit *switchbot.AuditLog
+}
+
+type onCfg struct {
+	Addr       string
+	TimeoutSec int
+	WaitResp   bool
+	IfNeeded   bool
+	Verify     bool
+	VerifySec  int
+	retryCfg
+}
+
+// Run executes parse args and pass args to RunContext.
+func (c *OnCommand) Run(args []string) int {
+	cfg, parseStatus := c.parseArgs(args)
+
+	if parseStatus != 0 {
+		return parseStatus
+	}
+
+	changed, err := c.runWithRetry(context.Background(), cfg)
+	if err != nil {
+		msg := fmt.Sprintf("Failed to turn on SwitchBot: %s", err.Error())
+		c.UI.Error(msg)
+		return 1
+	}
+
+	if cfg.IfNeeded {
+		if changed {
+			c.UI.Output("changed")
+		} else {
+			c.UI.Output("unchanged")
+		}
+	}
+	return 0
+}
+
+// ConnectAndOn executes connect and on.
+// It reports whether on command was triggered.
+func (c *OnCommand) ConnectAndOn(ctx context.Context, cfg *onCfg) (bool, error) {
+	if cfg.Verify {
+		opts := &switchbot.VerifyOptions{
+			ConnectTimeout: time.Duration(cfg.TimeoutSec) * time.Second,
+			Window:         time.Duration(cfg.VerifySec) * time.Second,
+			IfNeeded:       cfg.IfNeeded,
+		}
+		return switchbot.SwitchVerified(ctx, cfg.Addr, true, opts)
+	}
+
+	bot, err := switchbot.Connect(ctx, cfg.Addr, time.Duration(cfg.TimeoutSec)*time.Second)
+	if err != nil {
+		return false, err
+	}
+	defer bot.Disconnect()
+
+	if cfg.IfNeeded {
+		return bot.EnsureOn(cfg.WaitResp)
+	}
+	if err := bot.On(cfg.WaitResp); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Help represents help message for on command.
+func (c *OnCommand) Help() string {
+	helpText := `
+Usage: switchbot on [options] ADDRESS
+  Will execute on command against a SwitchBot in on/off mode specified by ADDRESS.
+
+Options:
+  -timeout=10                 Scan timeout seconds before connection. (Default 10)
+  -max-retry=0                Maximum retry count. (Default 0, or no limit with -retry-max-elapsed)
+  -retry-backoff=BACKOFF      Interval of retries. 'constant', 'exp' and 'jitter' are available.
+                              (Default 'constant')
+  -retry-interval=1s          Interval before the first retry. (Default 1s)
+  -retry-max-elapsed=0        Give up retries after the duration such as '30s'. (Default no limit)
+  -wait=true                  Wait success/failure response from SwitchBot. (Default true)
+  -if-needed=false            Skip on command if SwitchBot advertises it is already on,
+                              and print whether state was changed. (Default false)
+  -verify=false               Confirm SwitchBot advertises it is on after the command,
+                              and retry the command up to 3 times if not. (Default false)
+  -verify-window=5            Seconds to wait for the advertisement of each command. (Default 5)
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis represents synopsis message for on command.
+func (c *OnCommand) Synopsis() string {
+	return "Trigger on command"
+}
+
+func (c *OnCommand) parseArgs(args []string) (*onCfg, int) {
+	cfg := &onCfg{}
+	flags := flag.NewFlagSet("on", flag.ContinueOnError)
+	flags.IntVar(&cfg.TimeoutSec, "timeout", 10, "")
+	addRetryFlags(flags, &cfg.retryCfg)
+	flags.BoolVar(&cfg.WaitResp, "wait", true, "")
+	flags.BoolVar(&cfg.IfNeeded, "if-needed", false, "")
+	flags.BoolVar(&cfg.Verify, "verify", false, "")
+	flags.IntVar(&cfg.VerifySec, "verify-window", 5, "")
+	flags.Usage = func() {
+		c.UI.Info(c.Help())
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return cfg, 127
+	}
+
+	if !cfg.valid() {
+		flags.Usage()
+		return cfg, 127
+	}
+
+	args = flags.Args()
+	if len(args) != 1 {
+		flags.Usage()
+		return cfg, 127
+	}
+	addr, ok := parseAddr(c.UI, args[0])
+	if !ok {
+		return cfg, 127
+	}
+	cfg.Addr = addr
+	return cfg, 0
+}
+
+func (c *OnCommand) runWithRetry(ctx context.Context, cfg *onCfg) (bool, error) {
+	var changed bool
+	f := func() error {
+		var err error
+		changed, err = c.ConnectAndOn(ctx, cfg)
+		return err
+	}
+	p := cfg.policy(switchbot.BackoffConstant)
+	return changed, retryAndAudit(ctx, c.UI, c.Audit, cfg.Addr, "on", f, p)
+}
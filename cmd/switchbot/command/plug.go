@@ -0,0 +1,200 @@
+package command
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"github.com/yasuoza/switchbot-ble-go/v2/pkg/switchbot"
+)
+
+// PlugCommand reperesents plug command.
+type PlugCommand struct {
+	UI *cli.BasicUi
+	// Audit is audit log which actions are appended to. Actions are not logged if nil.
+	Audit *switchbot.AuditLog
+}
+
+type plugCfg struct {
+	Action     string
+	Addr       string
+	Format     string
+	TimeoutSec int
+	WaitResp   bool
+	retryCfg
+}
+
+// Run executes parse args and pass args to RunContext.
+func (c *PlugCommand) Run(args []string) int {
+	cfg, parseStatus := c.parseArgs(args)
+
+	if parseStatus != 0 {
+		return parseStatus
+	}
+
+	if cfg.Action == "info" {
+		return c.runInfo(context.Background(), cfg)
+	}
+
+	if err := c.runWithRetry(context.Background(), cfg); err != nil {
+		msg := fmt.Sprintf("Failed to %s Plug Mini: %s", cfg.Action, err.Error())
+		c.UI.Error(msg)
+		return 1
+	}
+
+	return 0
+}
+
+// ConnectAndTrigger executes connect and action specified by cfg.
+func (c *PlugCommand) ConnectAndTrigger(ctx context.Context, cfg *plugCfg) error {
+	plug, err := switchbot.ConnectPlugMini(ctx, cfg.Addr, time.Duration(cfg.TimeoutSec)*time.Second)
+	if err != nil {
+		return err
+	}
+	defer plug.Disconnect()
+
+	switch cfg.Action {
+	case "on":
+		err = plug.On(cfg.WaitResp)
+	case "off":
+		err = plug.Off(cfg.WaitResp)
+	case "toggle":
+		err = plug.Toggle(cfg.WaitResp)
+	}
+	return err
+}
+
+// Help represents help message for plug command.
+func (c *PlugCommand) Help() string {
+	helpText := `
+Usage: switchbot plug [options] on|off|toggle|info ADDRESS
+  Will execute on, off or toggle command against a Plug Mini specified by ADDRESS.
+  info will show current power state and load of the Plug Mini.
+
+Options:
+  -format=table               Output format of info. 'table' and 'json' are available.
+  -timeout=10                 Scan timeout seconds before connection. (Default 10)
+  -max-retry=0                Maximum retry count. (Default 0, or no limit with -retry-max-elapsed)
+  -retry-backoff=BACKOFF      Interval of retries. 'constant', 'exp' and 'jitter' are available.
+                              (Default 'exp' for info and 'constant' for others)
+  -retry-interval=1s          Interval before the first retry. (Default 1s)
+  -retry-max-elapsed=0        Give up retries after the duration such as '30s'. (Default no limit)
+  -wait=true                  Wait success/failure response from Plug Mini. (Default true)
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis represents synopsis message for plug command.
+func (c *PlugCommand) Synopsis() string {
+	return "Control Plug Mini or show its power state"
+}
+
+func (c *PlugCommand) parseArgs(args []string) (*plugCfg, int) {
+	cfg := &plugCfg{}
+	flags := flag.NewFlagSet("plug", flag.ContinueOnError)
+	flags.StringVar(&cfg.Format, "format", "table", "")
+	flags.IntVar(&cfg.TimeoutSec, "timeout", 10, "")
+	addRetryFlags(flags, &cfg.retryCfg)
+	flags.BoolVar(&cfg.WaitResp, "wait", true, "")
+	flags.Usage = func() {
+		c.UI.Info(c.Help())
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return cfg, 127
+	}
+
+	if !cfg.valid() {
+		flags.Usage()
+		return cfg, 127
+	}
+
+	args = flags.Args()
+	if len(args) != 2 || (cfg.Format != "table" && cfg.Format != "json") {
+		flags.Usage()
+		return cfg, 127
+	}
+
+	switch args[0] {
+	case "on", "off", "toggle", "info":
+	default:
+		flags.Usage()
+		return cfg, 127
+	}
+
+	cfg.Action = args[0]
+	addr, ok := parseAddr(c.UI, args[1])
+	if !ok {
+		return cfg, 127
+	}
+	cfg.Addr = addr
+	return cfg, 0
+}
+
+func (c *PlugCommand) runInfo(ctx context.Context, cfg *plugCfg) int {
+	var errTmpl string
+	if cfg.Format == "json" {
+		errTmpl = `{"error": "Failed to retreive info from Plug Mini: %s"}`
+	} else {
+		errTmpl = "Failed to retreive info from Plug Mini: %s"
+	}
+
+	var info *switchbot.PlugMiniInfo
+	f := func() error {
+		var err error
+		info, err = switchbot.GetPlugMiniInfo(ctx, cfg.Addr, time.Duration(cfg.TimeoutSec)*time.Second)
+		return err
+	}
+	p := cfg.policy(switchbot.BackoffExponential)
+	if err := p.Do(ctx, f); err != nil {
+		msg := fmt.Sprintf(errTmpl, err.Error())
+		c.UI.Error(msg)
+		return 1
+	}
+
+	if cfg.Format == "json" {
+		if err := printAsJSON(info); err != nil {
+			msg := fmt.Sprintf(errTmpl, err.Error())
+			c.UI.Error(msg)
+			return 1
+		}
+	} else {
+		printPlugAsTable(info, c.UI.Writer)
+	}
+
+	return 0
+}
+
+func printPlugAsTable(i *switchbot.PlugMiniInfo, writer io.Writer) {
+	var power string
+	if i.On {
+		power = "on"
+	} else {
+		power = "off"
+	}
+
+	data := []string{
+		power,
+		fmt.Sprintf("%0.1f", i.Load),
+		fmt.Sprintf("%v", i.Overload),
+		fmt.Sprintf("%d", i.WiFiRSSI),
+	}
+
+	table := newTable(writer)
+	table.SetHeader([]string{"Power", "Load(W)", "Overload", "WiFi RSSI(dBm)"})
+	table.Append(data)
+	table.Render()
+}
+
+func (c *PlugCommand) runWithRetry(ctx context.Context, cfg *plugCfg) error {
+	f := func() error {
+		return c.ConnectAndTrigger(ctx, cfg)
+	}
+	p := cfg.policy(switchbot.BackoffConstant)
+	return retryAndAudit(ctx, c.UI, c.Audit, cfg.Addr, cfg.Action, f, p)
+}
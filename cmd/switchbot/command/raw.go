@@ -0,0 +1,207 @@
+package command
+
+import (
+	"context"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"github.com/yasuoza/switchbot-ble-go/v2/pkg/switchbot"
+)
+
+// RawCommand reperesents raw command.
+type RawCommand struct {
+	UI *cli.BasicUi
+	// Audit is audit log which commands are appended to. Commands are not logged if nil.
+	Audit *switchbot.AuditLog
+}
+
+type rawCfg struct {
+	Addr       string
+	Cmd        []byte
+	Format     string
+	Password   string
+	KeyID      string
+	Key        string
+	TimeoutSec int
+	WaitResp   bool
+	retryCfg
+}
+
+type rawResult struct {
+	Addr     string      `json:"addr"`
+	Model    string      `json:"model,omitempty"`
+	Command  string      `json:"command"`
+	Response string      `json:"response,omitempty"`
+	Decoded  interface{} `json:"decoded,omitempty"`
+}
+
+// Run executes parse args and pass args to RunContext.
+func (c *RawCommand) Run(args []string) int {
+	cfg, parseStatus := c.parseArgs(args)
+
+	if parseStatus != 0 {
+		return parseStatus
+	}
+
+	var errTmpl string
+	if cfg.Format == "json" {
+		errTmpl = `{"error": "Failed to send %x to SwitchBot: %s"}`
+	} else {
+		errTmpl = "Failed to send %x to SwitchBot: %s"
+	}
+
+	ctx := context.Background()
+	var res *rawResult
+	f := func() error {
+		var err error
+		res, err = c.ConnectAndSend(ctx, cfg)
+		return err
+	}
+	p := cfg.policy(switchbot.BackoffConstant)
+	action := fmt.Sprintf("raw %x", cfg.Cmd)
+	if err := retryAndAudit(ctx, c.UI, c.Audit, cfg.Addr, action, f, p); err != nil {
+		msg := fmt.Sprintf(errTmpl, cfg.Cmd, err.Error())
+		c.UI.Error(msg)
+		return 1
+	}
+
+	if cfg.Format == "json" {
+		if err := printAsJSON(res); err != nil {
+			msg := fmt.Sprintf(errTmpl, cfg.Cmd, err.Error())
+			c.UI.Error(msg)
+			return 1
+		}
+		return 0
+	}
+
+	c.UI.Output(fmt.Sprintf("Command:  %s", res.Command))
+	if res.Response != "" {
+		c.UI.Output(fmt.Sprintf("Response: %s", res.Response))
+	}
+	if res.Decoded != nil {
+		c.UI.Output(fmt.Sprintf("Decoded:  %+v", res.Decoded))
+	}
+	return 0
+}
+
+// ConnectAndSend connects to SwitchBot and writes command specified by cfg.
+func (c *RawCommand) ConnectAndSend(ctx context.Context, cfg *rawCfg) (*rawResult, error) {
+	dev, err := switchbot.ConnectDevice(ctx, cfg.Addr, time.Duration(cfg.TimeoutSec)*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	defer dev.Disconnect()
+
+	switch {
+	case cfg.Key != "":
+		framer, err := switchbot.NewEncryptedFramer(cfg.KeyID, cfg.Key)
+		if err != nil {
+			return nil, err
+		}
+		dev.SetFramer(framer)
+	case cfg.Password != "":
+		dev.SetFramer(switchbot.NewPasswordFramer(cfg.Password))
+	}
+
+	resp, err := dev.Send(cfg.Cmd, cfg.WaitResp)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &rawResult{
+		Addr:     dev.Addr,
+		Command:  hex.EncodeToString(cfg.Cmd),
+		Response: hex.EncodeToString(resp),
+	}
+	if dev.Model == nil {
+		return res, nil
+	}
+	res.Model = dev.Model.Name
+	if resp != nil {
+		decoded, err := dev.Model.DecodeResponse(cfg.Cmd, resp)
+		if err != nil {
+			return nil, err
+		}
+		res.Decoded = decoded
+	}
+	return res, nil
+}
+
+// Help represents help message for raw command.
+func (c *RawCommand) Help() string {
+	helpText := `
+Usage: switchbot raw [options] ADDRESS HEX
+  Will write HEX bytes to the command characteristic of SwitchBot specified by ADDRESS
+  and show the response as hex. Fields of the response are also shown if the model
+  of the SwitchBot knows how to decode the response.
+  e.g. switchbot raw 11:11:11:11:11:11 5702
+
+Options:
+  -format=table               Output format. 'table' and 'json' are available.
+  -password=PASSWORD          Password of the SwitchBot.
+  -key-id=KEY_ID              Encryption key ID in hex. Used with -key.
+  -key=KEY                    Encryption key in hex, such as Lock's key.
+  -timeout=10                 Scan timeout seconds before connection. (Default 10)
+  -max-retry=0                Maximum retry count. (Default 0, or no limit with -retry-max-elapsed)
+  -retry-backoff=BACKOFF      Interval of retries. 'constant', 'exp' and 'jitter' are available.
+                              (Default 'constant')
+  -retry-interval=1s          Interval before the first retry. (Default 1s)
+  -retry-max-elapsed=0        Give up retries after the duration such as '30s'. (Default no limit)
+  -wait=true                  Wait response from SwitchBot. (Default true)
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis represents synopsis message for raw command.
+func (c *RawCommand) Synopsis() string {
+	return "Write raw bytes to SwitchBot for debugging"
+}
+
+func (c *RawCommand) parseArgs(args []string) (*rawCfg, int) {
+	cfg := &rawCfg{}
+	flags := flag.NewFlagSet("raw", flag.ContinueOnError)
+	flags.StringVar(&cfg.Format, "format", "table", "")
+	flags.StringVar(&cfg.Password, "password", "", "")
+	flags.StringVar(&cfg.KeyID, "key-id", "", "")
+	flags.StringVar(&cfg.Key, "key", "", "")
+	flags.IntVar(&cfg.TimeoutSec, "timeout", 10, "")
+	addRetryFlags(flags, &cfg.retryCfg)
+	flags.BoolVar(&cfg.WaitResp, "wait", true, "")
+	flags.Usage = func() {
+		c.UI.Info(c.Help())
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return cfg, 127
+	}
+
+	if !cfg.valid() {
+		flags.Usage()
+		return cfg, 127
+	}
+
+	args = flags.Args()
+	if len(args) != 2 || (cfg.Format != "table" && cfg.Format != "json") {
+		flags.Usage()
+		return cfg, 127
+	}
+
+	cmd, err := hex.DecodeString(args[1])
+	if err != nil || len(cmd) == 0 {
+		c.UI.Error(fmt.Sprintf("Invalid command %q, hex bytes such as 5702 are required", args[1]))
+		return cfg, 127
+	}
+
+	addr, ok := parseAddr(c.UI, args[0])
+	if !ok {
+		return cfg, 127
+	}
+	cfg.Addr = addr
+	cfg.Cmd = cmd
+	return cfg, 0
+}
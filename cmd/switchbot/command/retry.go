@@ -0,0 +1,48 @@
+package command
+
+import (
+	"flag"
+	"time"
+
+	"github.com/yasuoza/switchbot-ble-go/v2/pkg/switchbot"
+)
+
+// retryCfg is retry options shared by commands which connect to SwitchBot.
+type retryCfg struct {
+	MaxRetry   int
+	Backoff    string
+	Interval   time.Duration
+	MaxElapsed time.Duration
+}
+
+// addRetryFlags adds retry flags to flags.
+func addRetryFlags(flags *flag.FlagSet, cfg *retryCfg) {
+	flags.IntVar(&cfg.MaxRetry, "max-retry", 0, "")
+	flags.StringVar(&cfg.Backoff, "retry-backoff", "", "")
+	flags.DurationVar(&cfg.Interval, "retry-interval", switchbot.DefaultRetryInterval, "")
+	flags.DurationVar(&cfg.MaxElapsed, "retry-max-elapsed", 0, "")
+}
+
+// valid reports whether cfg is parsed from valid flags.
+func (cfg *retryCfg) valid() bool {
+	return cfg.MaxRetry >= 0 && cfg.Interval > 0 && cfg.MaxElapsed >= 0 &&
+		(cfg.Backoff == "" || switchbot.IsValidBackoff(cfg.Backoff))
+}
+
+// policy returns retry policy of cfg. backoff is used if -retry-backoff is not specified.
+// If -retry-max-elapsed is specified without -max-retry, retries are limited only by the duration.
+func (cfg *retryCfg) policy(backoff string) *switchbot.RetryPolicy {
+	if cfg.Backoff != "" {
+		backoff = cfg.Backoff
+	}
+	attempts := cfg.MaxRetry + 1
+	if cfg.MaxRetry == 0 && cfg.MaxElapsed > 0 {
+		attempts = 0
+	}
+	return &switchbot.RetryPolicy{
+		Backoff:     backoff,
+		Interval:    cfg.Interval,
+		MaxAttempts: attempts,
+		MaxElapsed:  cfg.MaxElapsed,
+	}
+}
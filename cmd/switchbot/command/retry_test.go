@@ -0,0 +1,36 @@
+package command
+
+import (
+	"flag"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestRetryCfgPolicy(t *testing.T) {
+	tests := []struct {
+		args        []string
+		maxAttempts int
+		maxElapsed  time.Duration
+	}{
+		{nil, 1, 0},
+		{[]string{"-max-retry=2"}, 3, 0},
+		{[]string{"-retry-backoff=exp", "-retry-max-elapsed=30s"}, 0, 30 * time.Second},
+		{[]string{"-max-retry=2", "-retry-max-elapsed=30s"}, 3, 30 * time.Second},
+	}
+	for _, tt := range tests {
+		cfg := &retryCfg{}
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags.SetOutput(io.Discard)
+		addRetryFlags(flags, cfg)
+		if err := flags.Parse(tt.args); err != nil {
+			t.Fatal(err)
+		}
+
+		p := cfg.policy("constant")
+		if p.MaxAttempts != tt.maxAttempts || p.MaxElapsed != tt.maxElapsed {
+			t.Errorf("%v: got MaxAttempts %d and MaxElapsed %s, want %d and %s",
+				tt.args, p.MaxAttempts, p.MaxElapsed, tt.maxAttempts, tt.maxElapsed)
+		}
+	}
+}
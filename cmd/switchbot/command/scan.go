@@ -2,8 +2,11 @@ package command
 
 import (
 	"context"
+	"encoding/hex"
 	"flag"
 	"fmt"
+	"io"
+	"sort"
 	"strings"
 	"time"
 
@@ -17,7 +20,29 @@ type ScanCommand struct {
 }
 
 type scanCfg struct {
-	TimeoutSec int
+	TimeoutSec    int
+	Format        string
+	Sort          string
+	MinRSSI       int
+	Models        []string
+	Prefix        string
+	Count         int
+	Expect        []string
+	ExpectAliases bool
+}
+
+// scanRow represents SwitchBot found during the whole scan.
+type scanRow struct {
+	Addr             string    `json:"addr"`
+	Model            string    `json:"model"`
+	RSSI             int       `json:"rssi"`
+	AddressType      string    `json:"address_type,omitempty"`
+	LocalName        string    `json:"local_name,omitempty"`
+	ManufacturerData string    `json:"manufacturer_data,omitempty"`
+	ServiceData      string    `json:"service_data,omitempty"`
+	FirstSeen        time.Time `json:"first_seen"`
+	LastSeen         time.Time `json:"last_seen"`
+	SeenCount        int       `json:"seen_count"`
 }
 
 // Run executes parse args and executes scan function.
@@ -27,41 +52,279 @@ func (c *ScanCommand) Run(args []string) int {
 		return parseStatus
 	}
 
-	ctx := context.Background()
-	err := switchbot.Scan(ctx, time.Duration(cfg.TimeoutSec)*time.Second, func(addr string) {
-		c.UI.Info(addr)
-	})
+	var errTmpl string
+	if cfg.Format == "json" {
+		errTmpl = `{"error": "Failed to scan SwitchBots: %s"}`
+	} else {
+		errTmpl = "Failed to scan SwitchBots: %s"
+	}
+
+	start := time.Now()
+	rows, err := c.scan(context.Background(), cfg)
 	if err != nil {
-		e := fmt.Sprintf("Failed to scan SwitchBots: %s", err.Error())
-		c.UI.Error(e)
+		c.UI.Error(fmt.Sprintf(errTmpl, err.Error()))
 		return 1
 	}
+	elapsed := time.Since(start)
+
+	sortScanRows(rows, cfg.Sort)
+	switch cfg.Format {
+	case "json":
+		if err := printAsJSON(rows); err != nil {
+			c.UI.Error(fmt.Sprintf(errTmpl, err.Error()))
+			return 1
+		}
+	case "table":
+		printScanRowsAsTable(rows, c.UI.Writer)
+		c.UI.Output(fmt.Sprintf("%d SwitchBots found in %s", len(rows), elapsed.Round(time.Millisecond)))
+	}
 
+	if missing := missingScanRows(rows, cfg.Expect); len(missing) != 0 {
+		c.UI.Error(fmt.Sprintf("Expected SwitchBots are not found: %s", strings.Join(missing, ", ")))
+		return 2
+	}
 	return 0
 }
 
+// scan scans SwitchBots until timeout or cfg.Count SwitchBots are found,
+// and returns them in order of discovery. In text format, addresses are
+// output as soon as found.
+func (c *ScanCommand) scan(ctx context.Context, cfg *scanCfg) ([]*scanRow, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	rows := []*scanRow{}
+	index := make(map[string]int)
+	callback := func(res *switchbot.ScanResult) {
+		if i, ok := index[res.Addr]; ok {
+			rows[i] = newScanRow(res)
+			return
+		}
+		if cfg.Count > 0 && len(rows) >= cfg.Count {
+			return
+		}
+		index[res.Addr] = len(rows)
+		rows = append(rows, newScanRow(res))
+		if cfg.Format == "text" {
+			c.UI.Info(res.Addr)
+		}
+		if cfg.Count > 0 && len(rows) >= cfg.Count {
+			cancel()
+		}
+	}
+
+	opts := []switchbot.Option{
+		switchbot.WithScanTimeout(time.Duration(cfg.TimeoutSec) * time.Second),
+		switchbot.WithMinRSSI(int16(cfg.MinRSSI)),
+		switchbot.WithModels(cfg.Models...),
+		switchbot.WithAddressPrefix(cfg.Prefix),
+		// Text format outputs addresses as soon as found, others aggregate the whole scan.
+		switchbot.WithDuplicates(cfg.Format != "text"),
+	}
+	err := switchbot.ScanWithOptions(ctx, callback, opts...)
+	return rows, err
+}
+
+func newScanRow(res *switchbot.ScanResult) *scanRow {
+	return &scanRow{
+		Addr:             res.Addr,
+		Model:            res.Model.Name,
+		RSSI:             res.RSSI,
+		AddressType:      res.AddressType,
+		LocalName:        res.LocalName,
+		ManufacturerData: hex.EncodeToString(res.ManufacturerData),
+		ServiceData:      hex.EncodeToString(res.ServiceData),
+		FirstSeen:        res.FirstSeen,
+		LastSeen:         res.LastSeen,
+		SeenCount:        res.SeenCount,
+	}
+}
+
+// sortScanRows sorts rows by key. RSSI is sorted from the strongest, and ties are
+// sorted by address. Empty key keeps order of discovery.
+func sortScanRows(rows []*scanRow, key string) {
+	less := map[string]func(a, b *scanRow) bool{
+		"rssi":  func(a, b *scanRow) bool { return a.RSSI > b.RSSI },
+		"name":  func(a, b *scanRow) bool { return a.LocalName < b.LocalName },
+		"model": func(a, b *scanRow) bool { return a.Model < b.Model },
+	}[key]
+	if less == nil {
+		return
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if less(rows[i], rows[j]) {
+			return true
+		}
+		if less(rows[j], rows[i]) {
+			return false
+		}
+		return rows[i].Addr < rows[j].Addr
+	})
+}
+
+// missingScanRows returns addresses of expect which are not found in rows.
+// Aliases are shown with their address.
+func missingScanRows(rows []*scanRow, expect []string) []string {
+	found := make(map[string]bool, len(rows))
+	for _, r := range rows {
+		found[r.Addr] = true
+	}
+
+	aliases := switchbot.CurrentAliases()
+	var missing []string
+	for _, addr := range expect {
+		if found[addr] {
+			continue
+		}
+		if alias, ok := aliases.Alias(switchbot.Address(addr)); ok {
+			missing = append(missing, fmt.Sprintf("%s (%s)", alias, addr))
+		} else {
+			missing = append(missing, addr)
+		}
+	}
+	return missing
+}
+
+func printScanRowsAsTable(rows []*scanRow, writer io.Writer) {
+	table := newTable(writer)
+	table.SetHeader([]string{"Address", "Model", "RSSI", "Type", "Name", "Manufacturer Data", "Service Data", "First Seen", "Last Seen", "Seen"})
+	for _, r := range rows {
+		table.Append([]string{
+			r.Addr,
+			r.Model,
+			fmt.Sprintf("%d", r.RSSI),
+			r.AddressType,
+			r.LocalName,
+			r.ManufacturerData,
+			r.ServiceData,
+			r.FirstSeen.Local().Format(time.RFC3339),
+			r.LastSeen.Local().Format(time.RFC3339),
+			fmt.Sprintf("%d", r.SeenCount),
+		})
+	}
+	table.Render()
+}
+
 func (c *ScanCommand) parseArgs(args []string) (*scanCfg, int) {
 	cfg := &scanCfg{}
-	flags := flag.NewFlagSet("press", flag.ContinueOnError)
+	var models, expect string
+	flags := flag.NewFlagSet("scan", flag.ContinueOnError)
 	flags.IntVar(&cfg.TimeoutSec, "timeout", 10, "")
+	flags.StringVar(&cfg.Format, "format", "text", "")
+	flags.StringVar(&cfg.Sort, "sort", "", "")
+	flags.IntVar(&cfg.MinRSSI, "min-rssi", 0, "")
+	flags.StringVar(&models, "model", "", "")
+	flags.StringVar(&cfg.Prefix, "prefix", "", "")
+	flags.IntVar(&cfg.Count, "count", 0, "")
+	flags.StringVar(&expect, "expect", "", "")
+	flags.BoolVar(&cfg.ExpectAliases, "expect-aliases", false, "")
 	flags.Usage = func() {
 		c.UI.Info(c.Help())
 	}
 	if err := flags.Parse(args); err != nil {
 		return cfg, 127
 	}
+
+	switch cfg.Format {
+	case "text", "table", "json":
+	default:
+		flags.Usage()
+		return cfg, 127
+	}
+	switch cfg.Sort {
+	case "", "rssi", "name", "model":
+	default:
+		flags.Usage()
+		return cfg, 127
+	}
+	if cfg.Sort != "" && cfg.Format == "text" {
+		c.UI.Error("-sort requires -format=table or -format=json")
+		return cfg, 127
+	}
+	if cfg.Count < 0 || cfg.MinRSSI > 0 || cfg.MinRSSI < -128 {
+		flags.Usage()
+		return cfg, 127
+	}
+
+	for _, name := range splitList(models) {
+		m, ok := lookupModel(name)
+		if !ok {
+			c.UI.Error(fmt.Sprintf("Unknown model %q, available models are %s", name, strings.Join(modelNames(), ", ")))
+			return cfg, 127
+		}
+		cfg.Models = append(cfg.Models, m.Name)
+	}
+
+	for _, s := range splitList(expect) {
+		addr, ok := parseAddr(c.UI, s)
+		if !ok || addr == switchbot.Nearest {
+			if ok {
+				c.UI.Error(fmt.Sprintf("%q can not be expected", s))
+			}
+			return cfg, 127
+		}
+		cfg.Expect = append(cfg.Expect, addr)
+	}
+	if cfg.ExpectAliases {
+		aliases := switchbot.CurrentAliases()
+		for _, name := range aliases.Names() {
+			cfg.Expect = append(cfg.Expect, aliases[name].String())
+		}
+	}
 	return cfg, 0
 }
 
+// splitList splits s separated by commas, ignoring empty elements.
+func splitList(s string) []string {
+	var ret []string
+	for _, e := range strings.Split(s, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			ret = append(ret, e)
+		}
+	}
+	return ret
+}
+
+// lookupModel returns registered model whose name equals name ignoring case.
+func lookupModel(name string) (*switchbot.Model, bool) {
+	for _, m := range switchbot.Models() {
+		if strings.EqualFold(m.Name, name) {
+			return m, true
+		}
+	}
+	return nil, false
+}
+
+func modelNames() []string {
+	var names []string
+	for _, m := range switchbot.Models() {
+		names = append(names, m.Name)
+	}
+	return names
+}
+
 // Help represents help message for scan command.
 func (c *ScanCommand) Help() string {
 	helpText := `
 Usage: switchbot scan [options]
   Will search for SwitchBots.
 	If SwitchBot is found, the MAC address will be output to STDOUT.
+  With -format=table or json, SwitchBots are listed after the scan with RSSI,
+  address type, local name, raw advertisement data and how many times they are seen.
+  Exits with status 2 if SwitchBots specified by -expect or -expect-aliases are not found.
 
 Options:
   -timeout=10                 Scan timeout seconds. (Default 10)
+  -format=text                Output format. 'text', 'table' and 'json' are available.
+                              'text' outputs addresses as soon as found. (Default 'text')
+  -sort=rssi                  Sort table or json by 'rssi', 'name' or 'model'. Not available with 'text'.
+                              (Default order of discovery)
+  -min-rssi=-80               Ignore SwitchBots weaker than the RSSI. (Default no limit)
+  -model=MODELS               Show only SwitchBots of models separated by commas, such as 'Bot,PlugMini'.
+  -prefix=PREFIX              Show only SwitchBots whose address starts with PREFIX, such as 'AA:BB'.
+  -count=0                    Stop scan after the number of SwitchBots are found. (Default no limit)
+  -expect=ADDRESSES           Addresses or aliases separated by commas which must be found.
+  -expect-aliases             Every SwitchBot in the aliases file must be found.
 `
 
 	return strings.TrimSpace(helpText)
@@ -7,7 +7,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/cenkalti/backoff/v4"
 	"github.com/mitchellh/cli"
 	"github.com/yasuoza/switchbot-ble-go/v2/pkg/switchbot"
 )
@@ -15,13 +14,15 @@ import (
 // UpCommand reperesents up command.
 type UpCommand struct {
 	UI *cli.BasicUi
+	// Audit is audit log which actions are appended to. Actions are not logged if nil.
+	Audit *switchbot.AuditLog
 }
 
 type upCfg struct {
 	Addr       string
 	TimeoutSec int
-	MaxRetry   int
 	WaitResp   bool
+	retryCfg
 }
 
 // Run executes parse args and pass args to RunContext.
@@ -62,8 +63,12 @@ Usage: switchbot up [options] ADDRESS
   Will execute up command against a SwitchBot specified by ADDRESS.
 
 Options:
-  -timeout=10                 Connection timeout seconds. (Default 10)
-  -max-retry=0                Maximum retry count. (Default 0)
+  -timeout=10                 Scan timeout seconds before connection. (Default 10)
+  -max-retry=0                Maximum retry count. (Default 0, or no limit with -retry-max-elapsed)
+  -retry-backoff=BACKOFF      Interval of retries. 'constant', 'exp' and 'jitter' are available.
+                              (Default 'constant')
+  -retry-interval=1s          Interval before the first retry. (Default 1s)
+  -retry-max-elapsed=0        Give up retries after the duration such as '30s'. (Default no limit)
   -wait=true                  Wait success/failure response from SwitchBot. (Default true)
 `
 
@@ -79,7 +84,7 @@ func (c *UpCommand) parseArgs(args []string) (*upCfg, int) {
 	cfg := &upCfg{}
 	flags := flag.NewFlagSet("off", flag.ContinueOnError)
 	flags.IntVar(&cfg.TimeoutSec, "timeout", 10, "")
-	flags.IntVar(&cfg.MaxRetry, "max-retry", 0, "")
+	addRetryFlags(flags, &cfg.retryCfg)
 	flags.BoolVar(&cfg.WaitResp, "wait", true, "")
 	flags.Usage = func() {
 		c.UI.Info(c.Help())
@@ -89,12 +94,21 @@ func (c *UpCommand) parseArgs(args []string) (*upCfg, int) {
 		return cfg, 127
 	}
 
+	if !cfg.valid() {
+		flags.Usage()
+		return cfg, 127
+	}
+
 	args = flags.Args()
 	if len(args) != 1 {
 		flags.Usage()
 		return cfg, 127
 	}
-	cfg.Addr = args[0]
+	addr, ok := parseAddr(c.UI, args[0])
+	if !ok {
+		return cfg, 127
+	}
+	cfg.Addr = addr
 	return cfg, 0
 }
 
@@ -102,7 +116,6 @@ func (c *UpCommand) runWithRetry(ctx context.Context, cfg *upCfg) error {
 	f := func() error {
 		return c.ConnectAndUp(ctx, cfg)
 	}
-	bo := backoff.NewConstantBackOff(1 * time.Second)
-	bw := backoff.WithMaxRetries(bo, uint64(cfg.MaxRetry))
-	return backoff.Retry(f, bw)
+	p := cfg.policy(switchbot.BackoffConstant)
+	return retryAndAudit(ctx, c.UI, c.Audit, cfg.Addr, "up", f, p)
 }
@@ -1,15 +1,42 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
 	"log"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/mitchellh/cli"
 	"github.com/yasuoza/switchbot-ble-go/v2/cmd/switchbot/command"
+	"github.com/yasuoza/switchbot-ble-go/v2/pkg/switchbot"
 )
 
 var Version = "current"
 
+// logger is logger of the CLI itself. Logs are discarded unless -verbose or -log-format is specified.
+var logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+type globalCfg struct {
+	Record    string
+	Replay    string
+	Verbose   bool
+	LogFormat string
+	Aliases   string
+	BatteryDB string
+	AuditLog  string
+	Timeouts  switchbot.Timeouts
+	Adapter   string
+
+	DeviceCache    string
+	DeviceCacheTTL time.Duration
+}
+
 func main() {
 	ui := &cli.BasicUi{
 		Reader:      os.Stdin,
@@ -17,24 +44,94 @@ func main() {
 		ErrorWriter: os.Stdout,
 	}
 
+	gcfg, args, err := parseGlobalFlags(os.Args[1:])
+	if err != nil {
+		log.Println(err)
+		os.Exit(127)
+	}
+
+	if err := setupLogger(gcfg); err != nil {
+		log.Println(err)
+		os.Exit(127)
+	}
+
+	switchbot.SetTimeouts(gcfg.Timeouts)
+
+	if err := setupAliases(gcfg); err != nil {
+		log.Println(err)
+		os.Exit(1)
+	}
+
+	if err := setupAdapters(gcfg); err != nil {
+		log.Println(err)
+		os.Exit(127)
+	}
+
+	closer, err := setupTransport(gcfg)
+	if err != nil {
+		log.Println(err)
+		os.Exit(1)
+	}
+
+	store := setupBatteryStore(gcfg)
+	audit := setupAuditLog(gcfg)
+	cache := setupDeviceCache(gcfg)
+
 	c := cli.NewCLI("switchbot", Version)
-	c.Args = os.Args[1:]
+	c.Args = args
+	c.HelpFunc = helpFunc
 	c.Commands = map[string]cli.CommandFactory{
+		"alias": func() (cli.Command, error) {
+			return &command.AliasCommand{UI: ui, Path: gcfg.Aliases}, nil
+		},
 		"scan": func() (cli.Command, error) {
 			return &command.ScanCommand{UI: ui}, nil
 		},
+		"blind": func() (cli.Command, error) {
+			return &command.BlindCommand{UI: ui, Audit: audit}, nil
+		},
+		"hub": func() (cli.Command, error) {
+			return &command.HubCommand{UI: ui}, nil
+		},
+		"humidifier": func() (cli.Command, error) {
+			return &command.HumidifierCommand{UI: ui, Audit: audit}, nil
+		},
 		"press": func() (cli.Command, error) {
-			return &command.PressCommand{UI: ui}, nil
+			return &command.PressCommand{UI: ui, Audit: audit}, nil
+		},
+		"on": func() (cli.Command, error) {
+			return &command.OnCommand{UI: ui, Audit: audit}, nil
+		},
+		"off": func() (cli.Command, error) {
+			return &command.OffCommand{UI: ui, Audit: audit}, nil
 		},
 		"up": func() (cli.Command, error) {
-			return &command.UpCommand{UI: ui}, nil
+			return &command.UpCommand{UI: ui, Audit: audit}, nil
 		},
 		"down": func() (cli.Command, error) {
-			return &command.DownCommand{UI: ui}, nil
+			return &command.DownCommand{UI: ui, Audit: audit}, nil
 		},
 		"info": func() (cli.Command, error) {
 			return &command.InfoCommand{UI: ui}, nil
 		},
+		"battery": func() (cli.Command, error) {
+			return &command.BatteryCommand{UI: ui, Store: store}, nil
+		},
+		"gatt": func() (cli.Command, error) {
+			return &command.GATTCommand{UI: ui}, nil
+		},
+		"light": func() (cli.Command, error) {
+			return &command.LightCommand{UI: ui, Audit: audit}, nil
+		},
+		"log": func() (cli.Command, error) {
+			return &command.LogCommand{UI: ui, Path: gcfg.AuditLog}, nil
+		},
+		"plug": func() (cli.Command, error) {
+			return &command.PlugCommand{UI: ui, Audit: audit}, nil
+		},
+		"raw": func() (cli.Command, error) {
+			return &command.RawCommand{UI: ui, Audit: audit}, nil
+		},
 	}
 
 	exitStatus, err := c.Run()
@@ -42,5 +139,246 @@ func main() {
 		log.Println(err)
 	}
 
+	if closer != nil {
+		closer.Close()
+	}
+	if store != nil {
+		store.Close()
+	}
+	if audit != nil {
+		audit.Close()
+	}
+	if cache != nil {
+		saveDeviceCache(cache)
+	}
 	os.Exit(exitStatus)
 }
+
+// parseGlobalFlags parses flags placed before the command name.
+// It returns rest of args which starts with the command name.
+func parseGlobalFlags(args []string) (*globalCfg, []string, error) {
+	cfg := &globalCfg{}
+	flags := flag.NewFlagSet("switchbot", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.StringVar(&cfg.Record, "record", "", "")
+	flags.StringVar(&cfg.Replay, "replay", "", "")
+	flags.BoolVar(&cfg.Verbose, "verbose", false, "")
+	flags.StringVar(&cfg.LogFormat, "log-format", "", "")
+	flags.StringVar(&cfg.Aliases, "aliases", defaultConfigPath("aliases.json"), "")
+	flags.StringVar(&cfg.BatteryDB, "battery-db", defaultConfigPath("battery.db"), "")
+	flags.StringVar(&cfg.AuditLog, "audit-log", "", "")
+	flags.StringVar(&cfg.Adapter, "adapter", "", "")
+	flags.StringVar(&cfg.DeviceCache, "device-cache", "", "")
+	flags.DurationVar(&cfg.DeviceCacheTTL, "device-cache-ttl", switchbot.DefaultDeviceCacheTTL, "")
+	flags.DurationVar(&cfg.Timeouts.Connect, "connect-timeout", 0, "")
+	flags.DurationVar(&cfg.Timeouts.Discovery, "discovery-timeout", 0, "")
+	flags.DurationVar(&cfg.Timeouts.Response, "response-timeout", 0, "")
+
+	// Only known flags are parsed here, so that -h and -v(-version) are handled by cli.
+	n := 0
+	for n < len(args) && strings.HasPrefix(args[n], "-") {
+		name := strings.SplitN(strings.TrimLeft(args[n], "-"), "=", 2)
+		f := flags.Lookup(name[0])
+		if f == nil {
+			break
+		}
+		if bf, ok := f.Value.(interface{ IsBoolFlag() bool }); len(name) == 1 && !(ok && bf.IsBoolFlag()) {
+			n++
+		}
+		n++
+	}
+	if n > len(args) {
+		n = len(args)
+	}
+
+	if err := flags.Parse(args[:n]); err != nil {
+		return nil, nil, err
+	}
+	return cfg, args[n:], nil
+}
+
+// setupLogger routes logs of switchbot package to stderr if -verbose or -log-format is specified.
+func setupLogger(cfg *globalCfg) error {
+	if !cfg.Verbose && cfg.LogFormat == "" {
+		return nil
+	}
+
+	opts := &slog.HandlerOptions{Level: slog.LevelInfo}
+	if cfg.Verbose {
+		opts.Level = slog.LevelDebug
+	}
+
+	var h slog.Handler
+	switch cfg.LogFormat {
+	case "", "text":
+		h = slog.NewTextHandler(os.Stderr, opts)
+	case "json":
+		h = slog.NewJSONHandler(os.Stderr, opts)
+	default:
+		return fmt.Errorf("unknown log format %q, 'text' and 'json' are available", cfg.LogFormat)
+	}
+	logger = slog.New(h)
+	switchbot.SetLogger(logger)
+	return nil
+}
+
+// defaultConfigPath returns path of file named name in user config directory.
+func defaultConfigPath(name string) string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "switchbot", name)
+}
+
+// setupAliases loads aliases so that commands accept alias as ADDRESS.
+func setupAliases(cfg *globalCfg) error {
+	if cfg.Aliases == "" {
+		return nil
+	}
+	a, err := switchbot.LoadAliases(cfg.Aliases)
+	if err != nil {
+		return err
+	}
+	switchbot.SetAliases(a)
+	return nil
+}
+
+// setupBatteryStore opens battery store and records battery readings to it.
+// Readings of replayed session are not recorded.
+// Battery history is optional, so failure is only logged and nil is returned.
+func setupBatteryStore(cfg *globalCfg) *switchbot.BoltBatteryStore {
+	if cfg.BatteryDB == "" || cfg.Replay != "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(cfg.BatteryDB), 0o755); err != nil {
+		logger.Warn("failed to open battery store", "error", err)
+		return nil
+	}
+	store, err := switchbot.OpenBatteryStore(cfg.BatteryDB)
+	if err != nil {
+		logger.Warn("failed to open battery store", "path", cfg.BatteryDB, "error", err)
+		return nil
+	}
+	switchbot.SetBatteryRecorder(store)
+	return store
+}
+
+// setupAuditLog opens audit log which actions are appended to.
+// Actions of replayed session are not appended.
+// Audit log is optional, so failure is only logged and nil is returned.
+func setupAuditLog(cfg *globalCfg) *switchbot.AuditLog {
+	if cfg.AuditLog == "" || cfg.Replay != "" {
+		return nil
+	}
+	audit, err := switchbot.OpenAuditLog(cfg.AuditLog)
+	if err != nil {
+		logger.Warn("failed to open audit log", "path", cfg.AuditLog, "error", err)
+		return nil
+	}
+	return audit
+}
+
+// setupDeviceCache opens device cache so that commands connect to known SwitchBots without scan.
+// SwitchBots of replayed session are not cached.
+// Device cache is optional, so failure is only logged and nil is returned.
+func setupDeviceCache(cfg *globalCfg) *switchbot.DeviceCache {
+	if cfg.DeviceCache == "" || cfg.Replay != "" {
+		return nil
+	}
+	cache, err := switchbot.OpenDeviceCache(cfg.DeviceCache, cfg.DeviceCacheTTL)
+	if err != nil {
+		logger.Warn("failed to open device cache", "path", cfg.DeviceCache, "error", err)
+		return nil
+	}
+	switchbot.SetDeviceCache(cache)
+	return cache
+}
+
+// saveDeviceCache writes SwitchBots found by the command to device cache.
+func saveDeviceCache(cache *switchbot.DeviceCache) {
+	stats := cache.Stats()
+	logger.Debug("device cache stats", "hits", stats.Hits, "misses", stats.Misses, "fallbacks", stats.Fallbacks)
+	if err := cache.Save(); err != nil {
+		logger.Warn("failed to save device cache", "error", err)
+	}
+}
+
+// setupAdapters sets adapters specified by -adapter.
+// If several adapters are separated by commas, the adapter with the strongest RSSI
+// to the target is used.
+func setupAdapters(cfg *globalCfg) error {
+	if cfg.Adapter == "" {
+		return nil
+	}
+
+	var as []*switchbot.Adapter
+	for _, id := range strings.Split(cfg.Adapter, ",") {
+		a, err := switchbot.NewAdapter(strings.TrimSpace(id))
+		if err != nil {
+			return err
+		}
+		as = append(as, a)
+	}
+	switchbot.SetAdapters(as...)
+	return nil
+}
+
+// setupTransport sets transport specified by global flags.
+// Returned closer must be closed after the command finishes.
+func setupTransport(cfg *globalCfg) (io.Closer, error) {
+	if cfg.Replay != "" {
+		f, err := os.Open(cfg.Replay)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		t, err := switchbot.NewReplayTransport(f)
+		if err != nil {
+			return nil, err
+		}
+		switchbot.SetTransport(t)
+	}
+
+	if cfg.Record != "" {
+		if len(switchbot.CurrentAdapters()) > 1 {
+			return nil, errors.New("-record can not be used with several adapters")
+		}
+		f, err := os.Create(cfg.Record)
+		if err != nil {
+			return nil, err
+		}
+		switchbot.SetTransport(switchbot.NewRecordingTransport(switchbot.CurrentTransport(), f))
+		return f, nil
+	}
+
+	return nil, nil
+}
+
+func helpFunc(commands map[string]cli.CommandFactory) string {
+	helpText := `
+Global options:
+  -verbose                    Write debug logs to STDERR.
+  -log-format=text            Log format. 'text' and 'json' are available.
+                              Info logs are written to STDERR if specified without -verbose.
+  -record=FILE                Record BLE session to FILE as JSON lines.
+  -replay=FILE                Replay BLE session recorded by -record instead of using Bluetooth adapter.
+  -aliases=FILE               Aliases file. (Default $XDG_CONFIG_HOME/switchbot/aliases.json)
+  -battery-db=FILE            Battery history database. Empty disables recording.
+                              Not recorded with -replay. (Default $XDG_CONFIG_HOME/switchbot/battery.db)
+  -audit-log=FILE             Audit log which actions are appended to. (Default disabled)
+                              Not appended with -replay.
+  -device-cache=FILE          Cache of SwitchBots to connect without scan. (Default disabled)
+                              Not used with -replay.
+  -device-cache-ttl=24h       Duration which found SwitchBots are cached for. (Default 24h)
+  -adapter=ID                 Bluetooth adapter such as 'hci1'. Adapters separated by commas
+                              such as 'hci0,hci1' are selected by RSSI to SwitchBot.
+                              Only the default adapter is available other than Linux.
+  -connect-timeout=0          Deadline of BLE connection such as '5s'. (Default no limit)
+  -discovery-timeout=0        Deadline of discovering SwitchBot service. (Default no limit)
+  -response-timeout=0         Deadline of response to each command. (Default no limit)
+                              Scan deadline is set by -timeout of each command.
+`
+	return cli.BasicHelpFunc("switchbot")(commands) + "\n" + strings.TrimSpace(helpText) + "\n"
+}
@@ -0,0 +1,138 @@
+package switchbot
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Adapter represents Bluetooth adapter which SwitchBots are scanned and connected by,
+// such as hci1 on Linux.
+type Adapter struct {
+	// ID is ID of the adapter. Empty ID is the default adapter.
+	ID string
+
+	transport Transport
+}
+
+// NewAdapter initializes Adapter of id. On Linux, id is name of HCI device such as "hci1".
+// Other platforms support only the default adapter, whose id is empty.
+// Existence of the adapter is checked when it is used.
+func NewAdapter(id string) (*Adapter, error) {
+	ba, err := bluetoothAdapter(id)
+	if err != nil {
+		return nil, err
+	}
+	return &Adapter{ID: id, transport: newAdapterTransport(id, ba)}, nil
+}
+
+// NewAdapterWithTransport initializes Adapter of id which uses t, such as RecordingTransport.
+func NewAdapterWithTransport(id string, t Transport) *Adapter {
+	return &Adapter{ID: id, transport: t}
+}
+
+// Transport returns Transport of the adapter.
+func (a *Adapter) Transport() Transport {
+	return a.transport
+}
+
+// String returns ID of the adapter, or "default" for the default adapter.
+func (a *Adapter) String() string {
+	if a.ID == "" {
+		return "default"
+	}
+	return a.ID
+}
+
+// SetAdapters sets adapters used by Scan, Connect and devices.
+// Scan uses the first adapter. If several adapters are given, Connect functions
+// use the adapter which receives the strongest RSSI from the target.
+// SetTransport overrides adapters.
+func SetAdapters(as ...*Adapter) {
+	defaultClient.configure(WithAdapters(as...))
+}
+
+// CurrentAdapters returns adapters set by SetAdapters.
+func CurrentAdapters() []*Adapter {
+	return defaultClient.current().adapters
+}
+
+// selectAdapter scans addr by every adapter of o and returns advertisement received
+// by the adapter with the strongest RSSI and options bound to the adapter.
+// Candidates are collected during nearestScanWindow. If no adapter receives
+// advertisement within the window, the first adapter which receives it is selected.
+func selectAdapter(ctx context.Context, o *options, addr string, models []string) (*Advertisement, *options, error) {
+	var target Address
+	if addr != Nearest {
+		t, err := o.resolve(addr)
+		if err != nil {
+			return nil, nil, err
+		}
+		target = t
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type found struct {
+		adv *Advertisement
+		o   *options
+		err error
+	}
+	foundc := make(chan found, len(o.adapters))
+	var wg sync.WaitGroup
+	for _, a := range o.adapters {
+		ao := *o
+		ao.transport = a.transport
+		ao.adapters = nil
+		ao.logger = o.logger.With("adapter", a.String())
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var adv *Advertisement
+			var err error
+			if addr == Nearest {
+				adv, err = scanNearest(ctx, &ao, models)
+			} else {
+				adv, err = scanTarget(ctx, &ao, target, ao.timeouts.Scan, nil)
+			}
+			foundc <- found{adv: adv, o: &ao, err: err}
+		}()
+	}
+	// Stop scans of other adapters before connecting by the selected one.
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	window := time.NewTimer(nearestScanWindow)
+	defer window.Stop()
+
+	var best *found
+	var lastErr error
+	pending := len(o.adapters)
+	windowc := window.C
+	for pending > 0 && (best == nil || windowc != nil) {
+		select {
+		case f := <-foundc:
+			pending--
+			if f.err != nil {
+				lastErr = f.err
+				continue
+			}
+			f.o.logger.Debug("adapter candidate", "addr", f.adv.Addr, "rssi", f.adv.RSSI)
+			if best == nil || f.adv.RSSI > best.adv.RSSI {
+				best = &f
+			}
+		case <-windowc:
+			windowc = nil
+		}
+	}
+
+	if best == nil {
+		return nil, nil, lastErr
+	}
+	best.o.logger.Debug("adapter selected", "addr", best.adv.Addr, "rssi", best.adv.RSSI)
+	return best.adv, best.o, nil
+}
@@ -0,0 +1,19 @@
+package switchbot
+
+import "tinygo.org/x/bluetooth"
+
+// bluetoothAdapter returns BlueZ adapter of id such as "hci1".
+func bluetoothAdapter(id string) (*bluetooth.Adapter, error) {
+	if id == "" {
+		return bluetooth.DefaultAdapter, nil
+	}
+	return bluetooth.NewAdapter(id), nil
+}
+
+// addressType returns address type of addr reported by BlueZ.
+func addressType(addr bluetooth.Address) string {
+	if addr.IsRandom() {
+		return AddressTypeRandom
+	}
+	return AddressTypePublic
+}
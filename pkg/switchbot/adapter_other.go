@@ -0,0 +1,22 @@
+//go:build !linux
+
+package switchbot
+
+import (
+	"fmt"
+
+	"tinygo.org/x/bluetooth"
+)
+
+// bluetoothAdapter returns the default adapter. Other adapters are not supported other than Linux.
+func bluetoothAdapter(id string) (*bluetooth.Adapter, error) {
+	if id != "" {
+		return nil, fmt.Errorf("adapter %q is not available, only the default adapter is supported on this platform", id)
+	}
+	return bluetooth.DefaultAdapter, nil
+}
+
+// addressType returns empty address type, since it is not reported other than Linux.
+func addressType(addr bluetooth.Address) string {
+	return ""
+}
@@ -0,0 +1,75 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestConnectSelectsAdapterByRSSI(t *testing.T) {
+	orig := nearestScanWindow
+	nearestScanWindow = 10 * time.Millisecond
+	t.Cleanup(func() {
+		nearestScanWindow = orig
+	})
+
+	far := newFakeBotTransport()
+	far.adv.RSSI = -85
+	near := newFakeBotTransport()
+	near.adv.RSSI = -45
+	missing := newFakeBotTransport()
+	missing.adv.Addr = "AA:BB:CC:DD:EE:FF"
+
+	as := []*Adapter{
+		NewAdapterWithTransport("hci0", far),
+		NewAdapterWithTransport("hci1", near),
+		NewAdapterWithTransport("hci2", missing),
+	}
+	bot, err := ConnectWithOptions(context.Background(), "11:22:33:44:55:66",
+		WithAdapters(as...), WithScanTimeout(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bot.Press(true); err != nil {
+		t.Fatal(err)
+	}
+	bot.Disconnect()
+
+	if len(near.written()) != 1 || len(far.written()) != 0 {
+		t.Errorf("expected command to be written by hci1, got hci0 %x, hci1 %x", far.written(), near.written())
+	}
+}
+
+func TestConnectSelectsAdapterNotFound(t *testing.T) {
+	a := newFakeBotTransport()
+	a.adv.Addr = "AA:BB:CC:DD:EE:FF"
+	b := newFakeBotTransport()
+	b.adv.Addr = "AA:BB:CC:DD:EE:FF"
+
+	_, err := ConnectWithOptions(context.Background(), "11:22:33:44:55:66",
+		WithAdapters(NewAdapterWithTransport("hci0", a), NewAdapterWithTransport("hci1", b)),
+		WithScanTimeout(10*time.Millisecond))
+	var terr *TimeoutError
+	if !errors.As(err, &terr) || terr.Phase != PhaseScan {
+		t.Fatalf("expected TimeoutError of scan, got %v", err)
+	}
+}
+
+func TestSetAdapters(t *testing.T) {
+	prev := CurrentTransport()
+	t.Cleanup(func() {
+		SetTransport(prev)
+	})
+
+	ft := newFakeBotTransport()
+	SetAdapters(NewAdapterWithTransport("hci1", ft), NewAdapterWithTransport("hci2", newFakeBotTransport()))
+	if CurrentTransport() != ft || len(CurrentAdapters()) != 2 {
+		t.Errorf("expected the first adapter to be used, got %v", CurrentAdapters())
+	}
+
+	SetTransport(ft)
+	if len(CurrentAdapters()) != 0 {
+		t.Errorf("expected SetTransport to override adapters, got %v", CurrentAdapters())
+	}
+}
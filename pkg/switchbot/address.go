@@ -0,0 +1,89 @@
+package switchbot
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidAddress is returned when address is neither MAC address nor UUID.
+var ErrInvalidAddress = errors.New("invalid address")
+
+// Address represents normalized address of SwitchBot.
+// MAC address is normalized to upper case colon separated form such as
+// "AA:BB:CC:DD:EE:FF", and UUID used instead of MAC address on macOS
+// is normalized to lower case hyphenated form.
+type Address string
+
+// ParseAddress parses MAC address separated by colons, hyphens or nothing,
+// such as "aa-bb-cc-dd-ee-ff" and "AABBCCDDEEFF", or UUID such as
+// "0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0".
+func ParseAddress(s string) (Address, error) {
+	h := strings.NewReplacer(":", "", "-", "").Replace(strings.TrimSpace(s))
+	b, err := hex.DecodeString(h)
+	if err != nil || !validAddressFormat(s, len(b)) {
+		return "", fmt.Errorf("%w %q: MAC address such as AA:BB:CC:DD:EE:FF or UUID is required", ErrInvalidAddress, s)
+	}
+
+	if len(b) == 6 {
+		parts := make([]string, len(b))
+		for i, c := range b {
+			parts[i] = fmt.Sprintf("%02X", c)
+		}
+		return Address(strings.Join(parts, ":")), nil
+	}
+	h = hex.EncodeToString(b)
+	return Address(h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]), nil
+}
+
+// validAddressFormat reports whether s of n bytes is written in MAC address or UUID form.
+func validAddressFormat(s string, n int) bool {
+	s = strings.TrimSpace(s)
+	switch n {
+	case 6:
+		if len(s) == 12 {
+			return true
+		}
+		// 6 groups of 2 hex digits separated by the same separator.
+		if len(s) != 17 || (s[2] != ':' && s[2] != '-') {
+			return false
+		}
+		for i := 5; i < len(s); i += 3 {
+			if s[i] != s[2] {
+				return false
+			}
+		}
+		return true
+	case 16:
+		return len(s) == 32 || (len(s) == 36 && s[8] == '-' && s[13] == '-' && s[18] == '-' && s[23] == '-')
+	default:
+		return false
+	}
+}
+
+// String returns normalized address.
+func (a Address) String() string {
+	return string(a)
+}
+
+// IsMAC reports whether a is MAC address.
+func (a Address) IsMAC() bool {
+	return len(a) == 17
+}
+
+// hasAddressPrefix reports whether addr starts with prefix ignoring case and separators.
+func hasAddressPrefix(addr, prefix string) bool {
+	r := strings.NewReplacer(":", "", "-", "")
+	return strings.HasPrefix(strings.ToUpper(r.Replace(addr)), strings.ToUpper(r.Replace(prefix)))
+}
+
+// normalizeAddr returns normalized form of addr.
+// If addr can not be parsed, addr is returned as it is.
+func normalizeAddr(addr string) string {
+	a, err := ParseAddress(addr)
+	if err != nil {
+		return addr
+	}
+	return a.String()
+}
@@ -0,0 +1,47 @@
+package switchbot
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Address
+	}{
+		{"aa:bb:cc:dd:ee:ff", "AA:BB:CC:DD:EE:FF"},
+		{"AA-BB-CC-DD-EE-FF", "AA:BB:CC:DD:EE:FF"},
+		{"aabbccddeeff", "AA:BB:CC:DD:EE:FF"},
+		{" 11:22:33:44:55:66 ", "11:22:33:44:55:66"},
+		{"0F1E2D3C-4B5A-6978-8796-A5B4C3D2E1F0", "0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0"},
+		{"0f1e2d3c4b5a69788796a5b4c3d2e1f0", "0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0"},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseAddress(tt.in)
+		if err != nil {
+			t.Errorf("ParseAddress(%q) returned error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseAddress(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseAddressInvalid(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"AA:BB:CC:DD:EE",
+		"AA:BB:CC:DD:EE:GG",
+		"AA:BB-CC:DD-EE:FF",
+		"AAB:BCC:DDE:EFF",
+		"A:ABB:CC:DD:EE:FF",
+		"0f1e2d3c4b5a-69788796-a5b4c3d2e1f0",
+	} {
+		if _, err := ParseAddress(in); !errors.Is(err, ErrInvalidAddress) {
+			t.Errorf("ParseAddress(%q) expected ErrInvalidAddress, got %v", in, err)
+		}
+	}
+}
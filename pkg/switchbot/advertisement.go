@@ -0,0 +1,39 @@
+package switchbot
+
+import "tinygo.org/x/bluetooth"
+
+// woanCompanyID is Bluetooth SIG company identifier of Woan Technology, the maker of SwitchBot.
+const woanCompanyID = 0x0969
+
+var (
+	// serviceDataUUIDs are UUIDs which SwitchBot uses to broadcast service data.
+	serviceDataUUIDs = []bluetooth.UUID{
+		bluetooth.New16BitUUID(0x0d00),
+		bluetooth.New16BitUUID(0xfd3d),
+	}
+)
+
+// newAdvertisement initializes Advertisement with bluetooth.ScanResult.
+// Payload of bluetooth.ScanResult may only stay valid until the next event,
+// so Advertisement keeps copies of them.
+func newAdvertisement(res bluetooth.ScanResult) *Advertisement {
+	adv := &Advertisement{
+		Addr:        res.Address.String(),
+		AddressType: addressType(res.Address),
+		RSSI:        res.RSSI,
+		LocalName:   res.LocalName(),
+	}
+	for _, el := range res.ManufacturerData() {
+		if el.CompanyID == woanCompanyID {
+			adv.ManufacturerData = append([]byte{}, el.Data...)
+		}
+	}
+	for _, el := range res.ServiceData() {
+		for _, uuid := range serviceDataUUIDs {
+			if el.UUID == uuid {
+				adv.ServiceData = append([]byte{}, el.Data...)
+			}
+		}
+	}
+	return adv
+}
@@ -0,0 +1,116 @@
+package switchbot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Nearest is target of Connect which selects SwitchBot with the strongest RSSI
+// among SwitchBots found during scan.
+const Nearest = "nearest"
+
+// Aliases maps user-assigned alias to address of SwitchBot.
+type Aliases map[string]Address
+
+// SetAliases sets aliases which Connect and other functions resolve addr argument with.
+func SetAliases(a Aliases) {
+	defaultClient.configure(WithAliases(a))
+}
+
+// CurrentAliases returns aliases set by SetAliases.
+func CurrentAliases() Aliases {
+	a := defaultClient.current().aliases
+	ret := make(Aliases, len(a))
+	for name, addr := range a {
+		ret[name] = addr
+	}
+	return ret
+}
+
+// LoadAliases reads aliases stored as JSON at path.
+// If the file does not exist, it returns empty Aliases.
+func LoadAliases(path string) (Aliases, error) {
+	a := Aliases{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return a, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return a, nil
+}
+
+// Save writes aliases as JSON to path.
+// Parent directories are created if needed.
+func (a Aliases) Save(path string) error {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Set assigns alias to addr.
+// alias must not be an address or Nearest.
+func (a Aliases) Set(alias, addr string) error {
+	if alias == "" || alias == Nearest {
+		return fmt.Errorf("alias %q is reserved", alias)
+	}
+	if _, err := ParseAddress(alias); err == nil {
+		return fmt.Errorf("alias %q must not be an address", alias)
+	}
+	parsed, err := ParseAddress(addr)
+	if err != nil {
+		return err
+	}
+	a[alias] = parsed
+	return nil
+}
+
+// Names returns sorted aliases.
+func (a Aliases) Names() []string {
+	names := make([]string, 0, len(a))
+	for name := range a {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Alias returns alias assigned to addr.
+func (a Aliases) Alias(addr Address) (string, bool) {
+	for _, name := range a.Names() {
+		if a[name] == addr {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// ResolveAddress parses s as address, or resolves s as alias set by SetAliases.
+func ResolveAddress(s string) (Address, error) {
+	return resolveAddress(s, defaultClient.current().aliases)
+}
+
+// resolveAddress parses s as address, or resolves s as alias of a.
+func resolveAddress(s string, a Aliases) (Address, error) {
+	addr, err := ParseAddress(s)
+	if err == nil {
+		return addr, nil
+	}
+	if addr, ok := a[s]; ok {
+		return ParseAddress(addr.String())
+	}
+	return "", fmt.Errorf("%w %q: address or known alias is required", ErrInvalidAddress, s)
+}
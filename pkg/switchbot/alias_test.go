@@ -0,0 +1,69 @@
+package switchbot
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func useTestAliases(t *testing.T, a Aliases) {
+	SetAliases(a)
+	t.Cleanup(func() {
+		SetAliases(nil)
+	})
+}
+
+func TestAliasesSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "switchbot", "aliases.json")
+
+	a, err := LoadAliases(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a) != 0 {
+		t.Fatalf("expected empty aliases, got %v", a)
+	}
+
+	if err := a.Set("kitchen", "aa-bb-cc-dd-ee-ff"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadAliases(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded["kitchen"] != "AA:BB:CC:DD:EE:FF" {
+		t.Errorf("unexpected aliases %v", loaded)
+	}
+	if name, ok := loaded.Alias("AA:BB:CC:DD:EE:FF"); !ok || name != "kitchen" {
+		t.Errorf("expected kitchen, got %q", name)
+	}
+}
+
+func TestAliasesSetInvalid(t *testing.T) {
+	a := Aliases{}
+	for _, tt := range [][2]string{
+		{Nearest, "AA:BB:CC:DD:EE:FF"},
+		{"AABBCCDDEEFF", "AA:BB:CC:DD:EE:FF"},
+		{"kitchen", "kitchen"},
+	} {
+		if err := a.Set(tt[0], tt[1]); err == nil {
+			t.Errorf("expected error for alias %q of %q", tt[0], tt[1])
+		}
+	}
+}
+
+func TestResolveAddress(t *testing.T) {
+	useTestAliases(t, Aliases{"kitchen": "11:22:33:44:55:66"})
+
+	addr, err := ResolveAddress("kitchen")
+	if err != nil || addr != "11:22:33:44:55:66" {
+		t.Errorf("expected 11:22:33:44:55:66, got %q, %v", addr, err)
+	}
+	if _, err := ResolveAddress("bedroom"); !errors.Is(err, ErrInvalidAddress) {
+		t.Errorf("expected ErrInvalidAddress, got %v", err)
+	}
+}
@@ -0,0 +1,188 @@
+package switchbot
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Initiators of actions recorded in AuditEntry.
+const (
+	InitiatorCLI       = "cli"
+	InitiatorHTTP      = "http"
+	InitiatorScheduler = "scheduler"
+)
+
+// Outcomes of actions recorded in AuditEntry.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// AuditEntry represents an action executed against SwitchBot.
+type AuditEntry struct {
+	Time      time.Time `json:"time"`
+	Addr      string    `json:"addr"`
+	Alias     string    `json:"alias,omitempty"`
+	Action    string    `json:"action"`
+	Initiator string    `json:"initiator"`
+	Outcome   string    `json:"outcome"`
+	Error     string    `json:"error,omitempty"`
+	LatencyMS int64     `json:"latency_ms"`
+	Retries   int       `json:"retries"`
+}
+
+// NewAuditEntry initializes AuditEntry of action which started at start and finished with err.
+// Alias of addr is resolved with aliases set by SetAliases.
+func NewAuditEntry(addr, action, initiator string, start time.Time, retries int, err error) *AuditEntry {
+	return newAuditEntry(addr, action, initiator, start, retries, err, CurrentAliases())
+}
+
+// newAuditEntry is NewAuditEntry which resolves alias of addr with aliases.
+func newAuditEntry(addr, action, initiator string, start time.Time, retries int, err error, aliases Aliases) *AuditEntry {
+	e := &AuditEntry{
+		Time:      start,
+		Addr:      addr,
+		Action:    action,
+		Initiator: initiator,
+		Outcome:   OutcomeSuccess,
+		LatencyMS: time.Since(start).Milliseconds(),
+		Retries:   retries,
+	}
+	if a, err := ParseAddress(addr); err == nil {
+		e.Addr = a.String()
+		e.Alias, _ = aliases.Alias(a)
+	}
+	if err != nil {
+		e.Outcome = OutcomeFailure
+		e.Error = err.Error()
+	}
+	return e
+}
+
+// AuditRecorder records actions executed against SwitchBots.
+// AuditLog implements AuditRecorder.
+type AuditRecorder interface {
+	Append(e *AuditEntry) error
+}
+
+// SetAuditRecorder sets AuditRecorder which actions such as Press, On and Off of SwitchBots
+// connected after the call are appended to with initiator.
+// If r is nil, actions are not recorded. Actions are not recorded by default.
+func SetAuditRecorder(r AuditRecorder, initiator string) {
+	defaultClient.configure(WithAuditRecorder(r, initiator))
+}
+
+// auditor appends actions of connected SwitchBot to AuditRecorder.
+// A nil auditor appends nothing.
+type auditor struct {
+	r         AuditRecorder
+	initiator string
+	aliases   Aliases
+}
+
+// append appends action which started at start and finished with err.
+// Recording is best effort, failure is only logged by l.
+func (a *auditor) append(l *slog.Logger, addr, action string, start time.Time, err error) {
+	if a == nil {
+		return
+	}
+	e := newAuditEntry(addr, action, a.initiator, start, 0, err, a.aliases)
+	if aerr := a.r.Append(e); aerr != nil {
+		l.Warn("failed to write audit log", "action", action, "error", aerr)
+	}
+}
+
+// AuditLog appends AuditEntry to a file as JSON lines.
+type AuditLog struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// OpenAuditLog opens audit log at path for appending.
+// The file and its parent directories are created if needed.
+func OpenAuditLog(path string) (*AuditLog, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &AuditLog{f: f}, nil
+}
+
+// Append appends e to the audit log.
+func (l *AuditLog) Append(e *AuditEntry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, err = l.f.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the audit log.
+func (l *AuditLog) Close() error {
+	return l.f.Close()
+}
+
+// AuditFilter filters entries read by ReadAuditLog.
+// Zero values match every entry.
+type AuditFilter struct {
+	// Addr matches address or alias of the entry.
+	Addr  string
+	Since time.Time
+	Until time.Time
+}
+
+func (f *AuditFilter) match(e *AuditEntry) bool {
+	if f.Addr != "" && f.Addr != e.Addr && f.Addr != e.Alias {
+		if a, err := ParseAddress(f.Addr); err != nil || a.String() != e.Addr {
+			return false
+		}
+	}
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !e.Time.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
+// ReadAuditLog reads entries which match filter from r.
+func ReadAuditLog(r io.Reader, filter *AuditFilter) ([]*AuditEntry, error) {
+	if filter == nil {
+		filter = &AuditFilter{}
+	}
+
+	var ret []*AuditEntry
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		e := &AuditEntry{}
+		if err := json.Unmarshal(sc.Bytes(), e); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if filter.match(e) {
+			ret = append(ret, e)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
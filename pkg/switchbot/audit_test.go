@@ -0,0 +1,105 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAuditLog(t *testing.T) {
+	useTestAliases(t, Aliases{"heater": "11:22:33:44:55:66"})
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	l, err := OpenAuditLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := time.Date(2026, 10, 18, 22, 0, 0, 0, time.UTC)
+	entries := []*AuditEntry{
+		NewAuditEntry("11:22:33:44:55:66", "off", InitiatorScheduler, base, 0, nil),
+		NewAuditEntry("aa-bb-cc-dd-ee-ff", "press", InitiatorCLI, base.Add(time.Hour), 2, errors.New("timeout")),
+		NewAuditEntry("11:22:33:44:55:66", "on", InitiatorHTTP, base.Add(10*time.Hour), 0, nil),
+	}
+	for _, e := range entries {
+		if err := l.Append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if entries[0].Alias != "heater" || entries[1].Addr != "AA:BB:CC:DD:EE:FF" {
+		t.Errorf("unexpected entries %+v, %+v", entries[0], entries[1])
+	}
+	if entries[1].Outcome != OutcomeFailure || entries[1].Error != "timeout" || entries[1].Retries != 2 {
+		t.Errorf("unexpected failure entry %+v", entries[1])
+	}
+
+	tests := []struct {
+		filter  AuditFilter
+		actions []string
+	}{
+		{AuditFilter{}, []string{"off", "press", "on"}},
+		{AuditFilter{Addr: "heater"}, []string{"off", "on"}},
+		{AuditFilter{Addr: "aabbccddeeff"}, []string{"press"}},
+		{AuditFilter{Since: base.Add(time.Minute), Until: base.Add(10 * time.Hour)}, []string{"press"}},
+	}
+	for _, tt := range tests {
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ReadAuditLog(f, &tt.filter)
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(tt.actions) {
+			t.Errorf("filter %+v: expected %d entries, got %d", tt.filter, len(tt.actions), len(got))
+			continue
+		}
+		for i, e := range got {
+			if e.Action != tt.actions[i] {
+				t.Errorf("filter %+v: expected %s, got %s", tt.filter, tt.actions[i], e.Action)
+			}
+		}
+	}
+}
+
+// memoryAuditRecorder keeps appended entries in memory.
+type memoryAuditRecorder struct {
+	entries []*AuditEntry
+}
+
+func (r *memoryAuditRecorder) Append(e *AuditEntry) error {
+	r.entries = append(r.entries, e)
+	return nil
+}
+
+func TestClientAuditRecorder(t *testing.T) {
+	r := &memoryAuditRecorder{}
+	c := NewClient(WithTransport(newFakeBotTransport()), WithScanTimeout(time.Second),
+		WithAliases(Aliases{"heater": "11:22:33:44:55:66"}), WithAuditRecorder(r, InitiatorScheduler))
+	defer c.Close()
+
+	bot, err := c.Connect(context.Background(), "heater")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bot.Press(true); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r.entries) != 1 {
+		t.Fatalf("expected press to be recorded, got %d entries", len(r.entries))
+	}
+	e := r.entries[0]
+	if e.Action != "press" || e.Initiator != InitiatorScheduler || e.Alias != "heater" || e.Outcome != OutcomeSuccess {
+		t.Errorf("unexpected entry %+v", e)
+	}
+}
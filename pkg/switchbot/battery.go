@@ -0,0 +1,209 @@
+package switchbot
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Sources of BatteryReading.
+const (
+	BatterySourceInfo          = "info"
+	BatterySourceAdvertisement = "advertisement"
+)
+
+// BatteryReading represents battery level of SwitchBot at a time.
+type BatteryReading struct {
+	Addr   string    `json:"addr"`
+	Model  string    `json:"model,omitempty"`
+	Level  int       `json:"level"`
+	Time   time.Time `json:"time"`
+	Source string    `json:"source"`
+}
+
+// BatteryRecorder records battery readings.
+// BoltBatteryStore implements BatteryRecorder.
+type BatteryRecorder interface {
+	AddBatteryReading(r *BatteryReading) error
+}
+
+// batteryReporter is implemented by states which include battery level.
+type batteryReporter interface {
+	BatteryLevel() int
+}
+
+// batteryAdvertisementInterval is interval to record unchanged level from advertisements.
+const batteryAdvertisementInterval = time.Hour
+
+// batteryRecorder records battery readings to BatteryRecorder.
+// A nil batteryRecorder records nothing.
+type batteryRecorder struct {
+	sync.Mutex
+	r   BatteryRecorder
+	now func() time.Time
+	// last maps address to the latest reading recorded from advertisement,
+	// so that repeated advertisements of the same level are not recorded.
+	last map[string]*BatteryReading
+}
+
+// newBatteryRecorder returns batteryRecorder which records to r, or nil if r is nil.
+func newBatteryRecorder(r BatteryRecorder) *batteryRecorder {
+	if r == nil {
+		return nil
+	}
+	return &batteryRecorder{
+		r:    r,
+		now:  time.Now,
+		last: make(map[string]*BatteryReading),
+	}
+}
+
+// SetBatteryRecorder sets BatteryRecorder which receives battery levels obtained from
+// GetInfo and advertisements of SwitchBots found by scan.
+// If r is nil, battery levels are not recorded.
+func SetBatteryRecorder(r BatteryRecorder) {
+	defaultClient.configure(WithBatteryRecorder(r))
+}
+
+// record records battery level of state if state reports it.
+// Level from advertisement is skipped if the same level of addr is recorded
+// within batteryAdvertisementInterval.
+// Recording is best effort, failure is only logged by l.
+func (b *batteryRecorder) record(l *slog.Logger, addr, model, source string, state interface{}) {
+	br, ok := state.(batteryReporter)
+	if !ok || b == nil {
+		return
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	r := &BatteryReading{
+		Addr:   addr,
+		Model:  model,
+		Level:  br.BatteryLevel(),
+		Time:   b.now(),
+		Source: source,
+	}
+	if source == BatterySourceAdvertisement {
+		last := b.last[addr]
+		if last != nil && last.Level == r.Level && r.Time.Sub(last.Time) < batteryAdvertisementInterval {
+			return
+		}
+		b.last[addr] = r
+	}
+	if err := b.r.AddBatteryReading(r); err != nil {
+		l.Warn("failed to record battery", "addr", addr, "error", err)
+	}
+}
+
+// recordAdvertisement records battery level decoded from adv.
+func (b *batteryRecorder) recordAdvertisement(l *slog.Logger, adv *Advertisement) {
+	if b == nil {
+		return
+	}
+	m := matchModel(adv)
+	if m == nil || m.Decoder == nil {
+		return
+	}
+	state, err := m.Decoder(adv.ManufacturerData, adv.ServiceData)
+	if err != nil {
+		return
+	}
+	b.record(l, adv.Addr, m.Name, BatterySourceAdvertisement, state)
+}
+
+// Battery trends reported by BatteryStatus.
+const (
+	BatteryTrendUnknown = "unknown"
+	BatteryTrendFalling = "falling"
+	BatteryTrendStable  = "stable"
+	BatteryTrendRising  = "rising"
+)
+
+const (
+	// batteryReplacedJump is rise of level regarded as battery replacement.
+	batteryReplacedJump = 20
+	// batteryStableDrain is drain per day regarded as stable.
+	batteryStableDrain = 0.05
+	// minBatteryHistory is minimum history to estimate drain rate.
+	minBatteryHistory = time.Hour
+)
+
+// BatteryStatus represents battery level of SwitchBot summarized from its readings.
+type BatteryStatus struct {
+	Addr     string    `json:"addr"`
+	Model    string    `json:"model,omitempty"`
+	Level    int       `json:"level"`
+	Trend    string    `json:"trend"`
+	LastSeen time.Time `json:"last_seen"`
+	// DrainPerDay is decrease of level per day. It is 0 if the trend is unknown.
+	DrainPerDay float64 `json:"drain_per_day"`
+	// DaysRemaining is estimated days until battery runs out.
+	// It is nil unless the trend is falling.
+	DaysRemaining *float64 `json:"days_remaining,omitempty"`
+}
+
+// NewBatteryStatus summarizes readings sorted by time.
+// Drain rate is estimated with readings since the battery was last replaced.
+// If readings is empty, it returns nil.
+func NewBatteryStatus(readings []*BatteryReading) *BatteryStatus {
+	if len(readings) == 0 {
+		return nil
+	}
+
+	last := readings[len(readings)-1]
+	s := &BatteryStatus{
+		Addr:     last.Addr,
+		Model:    last.Model,
+		Level:    last.Level,
+		Trend:    BatteryTrendUnknown,
+		LastSeen: last.Time,
+	}
+
+	start := 0
+	for i := 1; i < len(readings); i++ {
+		if readings[i].Level-readings[i-1].Level >= batteryReplacedJump {
+			start = i
+		}
+	}
+	readings = readings[start:]
+	if last.Time.Sub(readings[0].Time) < minBatteryHistory {
+		return s
+	}
+
+	slope := batterySlope(readings)
+	s.DrainPerDay = -slope
+	switch {
+	case slope < -batteryStableDrain:
+		s.Trend = BatteryTrendFalling
+		days := float64(s.Level) / s.DrainPerDay
+		s.DaysRemaining = &days
+	case slope > batteryStableDrain:
+		s.Trend = BatteryTrendRising
+	default:
+		s.Trend = BatteryTrendStable
+	}
+	return s
+}
+
+// batterySlope returns change of level per day by least squares.
+func batterySlope(readings []*BatteryReading) float64 {
+	origin := readings[0].Time
+	n := float64(len(readings))
+
+	var sx, sy, sxx, sxy float64
+	for _, r := range readings {
+		x := r.Time.Sub(origin).Hours() / 24
+		y := float64(r.Level)
+		sx += x
+		sy += y
+		sxx += x * x
+		sxy += x * y
+	}
+	d := n*sxx - sx*sx
+	if d == 0 {
+		return 0
+	}
+	return (n*sxy - sx*sy) / d
+}
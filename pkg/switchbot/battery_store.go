@@ -0,0 +1,102 @@
+package switchbot
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var batteryBucket = []byte("battery")
+
+// BoltBatteryStore is BatteryRecorder which stores readings in a bbolt database.
+type BoltBatteryStore struct {
+	db *bolt.DB
+}
+
+// OpenBatteryStore opens bbolt database at path. The database is created if needed.
+// Opened store must be closed by Close.
+func OpenBatteryStore(path string) (*BoltBatteryStore, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	return &BoltBatteryStore{db: db}, nil
+}
+
+// Close closes the database.
+func (s *BoltBatteryStore) Close() error {
+	return s.db.Close()
+}
+
+// AddBatteryReading stores r.
+func (s *BoltBatteryStore) AddBatteryReading(r *BatteryReading) error {
+	v, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		root, err := tx.CreateBucketIfNotExists(batteryBucket)
+		if err != nil {
+			return err
+		}
+		b, err := root.CreateBucketIfNotExists([]byte(r.Addr))
+		if err != nil {
+			return err
+		}
+		return b.Put(batteryKey(r.Time), v)
+	})
+}
+
+// Addrs returns addresses of SwitchBots which have readings.
+func (s *BoltBatteryStore) Addrs() ([]string, error) {
+	var addrs []string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket(batteryBucket)
+		if root == nil {
+			return nil
+		}
+		return root.ForEach(func(k, v []byte) error {
+			addrs = append(addrs, string(k))
+			return nil
+		})
+	})
+	return addrs, err
+}
+
+// BatteryReadings returns readings of addr since since sorted by time.
+func (s *BoltBatteryStore) BatteryReadings(addr string, since time.Time) ([]*BatteryReading, error) {
+	var readings []*BatteryReading
+	err := s.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket(batteryBucket)
+		if root == nil {
+			return nil
+		}
+		b := root.Bucket([]byte(addr))
+		if b == nil {
+			return nil
+		}
+		c := b.Cursor()
+		for k, v := c.Seek(batteryKey(since)); k != nil; k, v = c.Next() {
+			r := &BatteryReading{}
+			if err := json.Unmarshal(v, r); err != nil {
+				return err
+			}
+			readings = append(readings, r)
+		}
+		return nil
+	})
+	return readings, err
+}
+
+// batteryKey returns key which sorts readings by time.
+func batteryKey(t time.Time) []byte {
+	k := make([]byte, 8)
+	ns := t.UnixNano()
+	if ns < 0 {
+		ns = 0
+	}
+	binary.BigEndian.PutUint64(k, uint64(ns))
+	return k
+}
@@ -0,0 +1,144 @@
+package switchbot
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func openTestBatteryStore(t *testing.T) *BoltBatteryStore {
+	s, err := OpenBatteryStore(filepath.Join(t.TempDir(), "battery.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		s.Close()
+	})
+	return s
+}
+
+func TestBatteryStore(t *testing.T) {
+	s := openTestBatteryStore(t)
+
+	base := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	for i, level := range []int{90, 89, 88} {
+		r := &BatteryReading{Addr: "11:22:33:44:55:66", Level: level, Time: base.Add(time.Duration(i) * time.Hour)}
+		if err := s.AddBatteryReading(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	addrs, err := s.Addrs()
+	if err != nil || len(addrs) != 1 || addrs[0] != "11:22:33:44:55:66" {
+		t.Fatalf("unexpected addrs %v, %v", addrs, err)
+	}
+
+	readings, err := s.BatteryReadings("11:22:33:44:55:66", base.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(readings) != 2 || readings[0].Level != 89 || readings[1].Level != 88 {
+		t.Errorf("unexpected readings %+v", readings)
+	}
+}
+
+func TestRecordBatteryFromGetInfo(t *testing.T) {
+	useTestTransport(t, newTestReplayTransport(t, "testdata/getinfo.jsonl"))
+	s := openTestBatteryStore(t)
+	SetBatteryRecorder(s)
+	t.Cleanup(func() {
+		SetBatteryRecorder(nil)
+	})
+
+	bot, err := Connect(context.Background(), "11:22:33:44:55:66", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bot.GetInfo(); err != nil {
+		t.Fatal(err)
+	}
+
+	readings, err := s.BatteryReadings("11:22:33:44:55:66", time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(readings) != 2 {
+		t.Fatalf("expected readings from advertisement and info, got %d", len(readings))
+	}
+	if readings[0].Source != BatterySourceAdvertisement || readings[1].Source != BatterySourceInfo {
+		t.Errorf("unexpected sources %q, %q", readings[0].Source, readings[1].Source)
+	}
+	if readings[1].Level != 79 || readings[1].Model != "Bot" {
+		t.Errorf("unexpected reading %+v", readings[1])
+	}
+}
+
+func TestNewBatteryStatus(t *testing.T) {
+	base := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	reading := func(d time.Duration, level int) *BatteryReading {
+		return &BatteryReading{Addr: "11:22:33:44:55:66", Level: level, Time: base.Add(d)}
+	}
+
+	// Battery was replaced on day 2, then drains 2% per day.
+	s := NewBatteryStatus([]*BatteryReading{
+		reading(0, 12),
+		reading(day, 10),
+		reading(2*day, 100),
+		reading(3*day, 98),
+		reading(4*day, 96),
+	})
+	if s.Trend != BatteryTrendFalling || s.Level != 96 {
+		t.Fatalf("unexpected status %+v", s)
+	}
+	if s.DrainPerDay < 1.99 || s.DrainPerDay > 2.01 {
+		t.Errorf("expected drain 2/day, got %f", s.DrainPerDay)
+	}
+	if s.DaysRemaining == nil || *s.DaysRemaining < 47.9 || *s.DaysRemaining > 48.1 {
+		t.Errorf("expected 48 days remaining, got %v", s.DaysRemaining)
+	}
+	if !s.LastSeen.Equal(base.Add(4 * day)) {
+		t.Errorf("unexpected last seen %v", s.LastSeen)
+	}
+
+	s = NewBatteryStatus([]*BatteryReading{reading(0, 80), reading(time.Minute, 80)})
+	if s.Trend != BatteryTrendUnknown || s.DaysRemaining != nil {
+		t.Errorf("expected unknown trend for short history, got %+v", s)
+	}
+
+	s = NewBatteryStatus([]*BatteryReading{reading(0, 80), reading(day, 80)})
+	if s.Trend != BatteryTrendStable {
+		t.Errorf("expected stable trend, got %+v", s)
+	}
+
+	if NewBatteryStatus(nil) != nil {
+		t.Error("expected nil for empty readings")
+	}
+}
+
+func TestRecordBatteryOncePerScan(t *testing.T) {
+	s := openTestBatteryStore(t)
+	SetBatteryRecorder(s)
+	t.Cleanup(func() {
+		SetBatteryRecorder(nil)
+	})
+
+	ft := newFakeBotTransport()
+	ft.others = []*Advertisement{ft.adv, ft.adv}
+	for i := 0; i < 2; i++ {
+		err := ScanWithOptions(context.Background(), func(*ScanResult) {},
+			WithTransport(ft), WithDuplicates(true), WithScanTimeout(10*time.Millisecond))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	readings, err := s.BatteryReadings("11:22:33:44:55:66", time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(readings) != 1 {
+		t.Errorf("expected unchanged level to be recorded once, got %d readings", len(readings))
+	}
+}
@@ -0,0 +1,100 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// BlindTilt represents SwitchBot Blind Tilt device.
+type BlindTilt struct {
+	Addr string
+
+	conn
+}
+
+func init() {
+	mustRegisterModel(&Model{
+		Name:       "BlindTilt",
+		ModelBytes: []byte{'x'},
+		Decoder: func(mfrData, svcData []byte) (interface{}, error) {
+			return NewBlindTiltInfoWithAdvertisement(mfrData, svcData)
+		},
+		Commands: map[string][]byte{
+			"open":       {0x57, 0x0f, 0x45, 0x01, 0x01, 0x01, 50},
+			"close-up":   {0x57, 0x0f, 0x45, 0x01, 0x01, 0x01, 100},
+			"close-down": {0x57, 0x0f, 0x45, 0x01, 0x01, 0x01, 0},
+			"stop":       {0x57, 0x0f, 0x45, 0x01, 0x00, 0x01},
+		},
+	})
+}
+
+// NewBlindTilt initializes blind tilt object.
+func NewBlindTilt(addr string) *BlindTilt {
+	return &BlindTilt{Addr: normalizeAddr(addr), conn: newConn(addr)}
+}
+
+// ConnectBlindTilt connects to SwitchBot Blind Tilt filter by addr argument.
+// If connection failed within timeout, ConnectBlindTilt returns error.
+func ConnectBlindTilt(ctx context.Context, addr string, timeout time.Duration) (*BlindTilt, error) {
+	return defaultClient.ConnectBlindTilt(ctx, addr, WithScanTimeout(timeout))
+}
+
+// ConnectBlindTilt connects to SwitchBot Blind Tilt filter by addr argument with opts.
+func (c *Client) ConnectBlindTilt(ctx context.Context, addr string, opts ...Option) (*BlindTilt, error) {
+	res, cn, err := c.connect(ctx, addr, opts, "BlindTilt")
+	if err != nil {
+		return nil, err
+	}
+	return &BlindTilt{Addr: res.Addr, conn: cn}, nil
+}
+
+// GetBlindTiltInfo retrieves Blind Tilt's current state from its advertisement.
+// If advertisement is not received within timeout, GetBlindTiltInfo returns error.
+func GetBlindTiltInfo(ctx context.Context, addr string, timeout time.Duration) (*BlindTiltInfo, error) {
+	return defaultClient.GetBlindTiltInfo(ctx, addr, WithScanTimeout(timeout))
+}
+
+// GetBlindTiltInfo retrieves Blind Tilt's current state from its advertisement with opts.
+func (c *Client) GetBlindTiltInfo(ctx context.Context, addr string, opts ...Option) (*BlindTiltInfo, error) {
+	res, err := c.scanAddr(ctx, addr, opts, "BlindTilt")
+	if err != nil {
+		return nil, err
+	}
+	if res.ManufacturerData == nil || res.ServiceData == nil {
+		return nil, errors.New("manufacturer data or service data is not found in advertisement")
+	}
+	return NewBlindTiltInfoWithAdvertisement(res.ManufacturerData, res.ServiceData)
+}
+
+// SetPosition tilts the Blind Tilt to pos.
+// pos must be between 0 and 100, 0 is closed down, 50 is open and 100 is closed up.
+func (b *BlindTilt) SetPosition(pos int, wait bool) error {
+	if pos < 0 || pos > 100 {
+		return fmt.Errorf("position must be between 0 and 100, got %d", pos)
+	}
+	_, err := b.act(fmt.Sprintf("position %d", pos), []byte{0x57, 0x0f, 0x45, 0x01, 0x01, 0x01, byte(pos)}, wait)
+	return err
+}
+
+// Open tilts the Blind Tilt to open position.
+func (b *BlindTilt) Open(wait bool) error {
+	return b.SetPosition(50, wait)
+}
+
+// CloseUp tilts the Blind Tilt to closed up position.
+func (b *BlindTilt) CloseUp(wait bool) error {
+	return b.SetPosition(100, wait)
+}
+
+// CloseDown tilts the Blind Tilt to closed down position.
+func (b *BlindTilt) CloseDown(wait bool) error {
+	return b.SetPosition(0, wait)
+}
+
+// Stop stops the Blind Tilt in motion.
+func (b *BlindTilt) Stop(wait bool) error {
+	_, err := b.act("stop", []byte{0x57, 0x0f, 0x45, 0x01, 0x00, 0x01}, wait)
+	return err
+}
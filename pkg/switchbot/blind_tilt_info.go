@@ -0,0 +1,55 @@
+package switchbot
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// BlindTiltInfo represents current SwitchBot Blind Tilt's information.
+type BlindTiltInfo struct {
+	Battery    int  `json:"battery"`
+	Position   int  `json:"position"`
+	InMotion   bool `json:"in_motion"`
+	LightLevel int  `json:"light_level"`
+	Calibrated bool `json:"calibrated"`
+}
+
+// NewBlindTiltInfoWithAdvertisement initialize BlindTiltInfo with manufacturer data
+// and service data of Blind Tilt's advertisement.
+func NewBlindTiltInfoWithAdvertisement(mfrData, svcData []byte) (*BlindTiltInfo, error) {
+	if len(mfrData) < 9 {
+		return nil, fmt.Errorf("blind tilt advertisement is too short: %d bytes", len(mfrData))
+	}
+	if len(svcData) < 3 {
+		return nil, fmt.Errorf("blind tilt service data is too short: %d bytes", len(svcData))
+	}
+
+	pos := int(mfrData[8] & 0x7f)
+	if pos > 100 {
+		pos = 100
+	}
+
+	return &BlindTiltInfo{
+		Battery:    int(svcData[2] & 0x7f),
+		Position:   pos,
+		InMotion:   (mfrData[8] & 0x80) != 0,
+		LightLevel: int(mfrData[7]>>4) & 0x0f,
+		Calibrated: (mfrData[7] & 0x01) != 0,
+	}, nil
+}
+
+// String returns formatted information
+func (i *BlindTiltInfo) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Battery: %d", i.Battery))
+	buf.WriteString(fmt.Sprintf(", Position: %d", i.Position))
+	buf.WriteString(fmt.Sprintf(", InMotion: %t", i.InMotion))
+	buf.WriteString(fmt.Sprintf(", LightLevel: %d", i.LightLevel))
+	buf.WriteString(fmt.Sprintf(", Calibrated: %t", i.Calibrated))
+	return buf.String()
+}
+
+// BatteryLevel returns battery level in percent.
+func (i *BlindTiltInfo) BatteryLevel() int {
+	return i.Battery
+}
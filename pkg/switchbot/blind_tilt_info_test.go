@@ -0,0 +1,36 @@
+package switchbot
+
+import "testing"
+
+func TestNewBlindTiltInfoWithAdvertisement(t *testing.T) {
+	mfr := []byte{0x60, 0x55, 0xf9, 0x11, 0x22, 0x33, 0x05, 0x51, 0xb2}
+	svc := []byte{0x78, 0x00, 0x5a}
+
+	got, err := NewBlindTiltInfoWithAdvertisement(mfr, svc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Battery != 90 {
+		t.Errorf("Battery is not correct, got %v", got.Battery)
+	}
+	if got.Position != 50 {
+		t.Errorf("Position is not correct, got %v", got.Position)
+	}
+	if got.InMotion != true {
+		t.Errorf("InMotion is not correct, got %v", got.InMotion)
+	}
+	if got.LightLevel != 5 {
+		t.Errorf("LightLevel is not correct, got %v", got.LightLevel)
+	}
+	if got.Calibrated != true {
+		t.Errorf("Calibrated is not correct, got %v", got.Calibrated)
+	}
+}
+
+func TestNewBlindTiltInfoWithShortAdvertisement(t *testing.T) {
+	mfr := []byte{0x60, 0x55, 0xf9, 0x11, 0x22, 0x33, 0x05, 0x51, 0xb2}
+
+	if _, err := NewBlindTiltInfoWithAdvertisement(mfr, nil); err == nil {
+		t.Fatal("expected error, but got nil")
+	}
+}
@@ -1,141 +1,157 @@
 package switchbot
 
 import (
-	"encoding/binary"
-	"errors"
-	"hash/crc32"
-	"strings"
-
-	"tinygo.org/x/bluetooth"
+	"fmt"
 )
 
 // Bot represents SwitchBot device.
 type Bot struct {
 	Addr string
 
-	dev *bluetooth.Device
+	conn
 
-	subschar *bluetooth.DeviceCharacteristic
-	cmdchar  *bluetooth.DeviceCharacteristic
+	// state is state learned from advertisement and updated by On and Off.
+	state *BotState
+}
 
-	pw []byte
+// Commands of Bot, which are registered as Model.Commands as well.
+var (
+	botPress = []byte{0x57, 0x01}
+	botOn    = []byte{0x57, 0x01, 0x01}
+	botOff   = []byte{0x57, 0x01, 0x02}
+	botDown  = []byte{0x57, 0x01, 0x03}
+	botUp    = []byte{0x57, 0x01, 0x04}
+	botInfo  = []byte{0x57, 0x02}
+)
 
-	subsque    chan []byte
-	subscribed bool
+func init() {
+	mustRegisterModel(&Model{
+		Name:       "Bot",
+		ModelBytes: []byte{'H'},
+		LocalNames: []string{"WoHand"},
+		Decoder: func(_, svcData []byte) (interface{}, error) {
+			return NewBotStateWithAdvertisement(svcData)
+		},
+		Commands: map[string][]byte{
+			"press": botPress,
+			"on":    botOn,
+			"off":   botOff,
+			"down":  botDown,
+			"up":    botUp,
+			"info":  botInfo,
+		},
+		ResponseDecoders: map[string]ResponseDecoder{
+			"5702": func(res []byte) (interface{}, error) {
+				if len(res) < 11 {
+					return nil, fmt.Errorf("bot info is too short: %d bytes", len(res))
+				}
+				return NewBotInfoWithRawInfo(res), nil
+			},
+			"5708": func(res []byte) (interface{}, error) {
+				if len(res) < 8 {
+					return nil, fmt.Errorf("timer is too short: %d bytes", len(res))
+				}
+				return ParseTimerBytes(res), nil
+			},
+		},
+	})
 }
 
 // NewBot initializes bot object.
+// addr is normalized in the same way as ParseAddress.
 func NewBot(addr string) *Bot {
-	b := &Bot{Addr: strings.ToLower(addr)}
-	b.subsque = make(chan []byte)
-	b.subscribed = false
-	return b
+	return &Bot{Addr: normalizeAddr(addr), conn: newConn(addr)}
 }
 
 // SetPassword sets SwitchBot's password.
 // If SwitchBot is configured to use password authentication,
 // you need to call SetPassword before calling Press/On/Off function.
 func (b *Bot) SetPassword(pw string) {
-	crc := crc32.ChecksumIEEE([]byte(pw))
-	bs := make([]byte, 4)
-	binary.BigEndian.PutUint32(bs[0:], crc)
-	b.pw = bs
-}
-
-// Subscribe subscribes to bot and waiting notification from SwitchBot.
-func (b *Bot) Subscribe() error {
-	err := b.subschar.EnableNotifications(func(info []byte) {
-		b.subsque <- info
-	})
-	if err != nil {
-		return err
-	}
-	b.subscribed = true
-	return nil
-}
-
-// Disconnect  disconnects current SwitchBot connection.
-func (b *Bot) Disconnect() error {
-	return b.dev.Disconnect()
+	b.SetFramer(NewPasswordFramer(pw))
 }
 
 // Press triggers press function for the SwitchBot.
 // SwitchBot must be set to press mode.
 func (b *Bot) Press(wait bool) error {
-	var cmd []byte
-	if b.encrypted() {
-		cmd = append([]byte{0x57, 0x11}, b.pw...)
-	} else {
-		cmd = []byte{0x57, 0x01}
-	}
-	_, err := b.trigger(cmd, wait)
+	_, err := b.act("press", botPress, wait)
 	return err
 }
 
 // On triggers on function for the SwitchBot.
 // SwitchBot must be set to On/Off mode.
 func (b *Bot) On(wait bool) error {
-	var cmd []byte
-	if b.encrypted() {
-		cmd = append(append([]byte{0x57, 0x11}, b.pw...), []byte{0x01}...)
-	} else {
-		cmd = []byte{0x57, 0x01, 0x01}
+	_, err := b.act("on", botOn, wait)
+	if err == nil && b.state != nil {
+		b.state.On = true
 	}
-	_, err := b.trigger(cmd, wait)
 	return err
 }
 
 // Off triggers off function for the SwitchBot.
 // SwitchBot must be set to On/Off mode.
 func (b *Bot) Off(wait bool) error {
-	var cmd []byte
-	if b.encrypted() {
-		cmd = append(append([]byte{0x57, 0x11}, b.pw...), []byte{0x02}...)
-	} else {
-		cmd = []byte{0x57, 0x01, 0x02}
+	_, err := b.act("off", botOff, wait)
+	if err == nil && b.state != nil {
+		b.state.On = false
 	}
-	_, err := b.trigger(cmd, wait)
 	return err
 }
 
+// State returns state of the SwitchBot learned from advertisement received by Connect.
+// State is updated by On and Off. If the state is unknown, State returns nil.
+func (b *Bot) State() *BotState {
+	return b.state
+}
+
+// EnsureOn triggers on function unless the SwitchBot is known to be on already.
+// It reports whether on function was triggered.
+// If the state is unknown or the SwitchBot is in press mode, on function is always triggered.
+func (b *Bot) EnsureOn(wait bool) (bool, error) {
+	if s := b.state; s != nil && s.StateMode && s.On {
+		b.logger.Info("skipped redundant command", "command", "on")
+		return false, nil
+	}
+	if err := b.On(wait); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// EnsureOff triggers off function unless the SwitchBot is known to be off already.
+// It reports whether off function was triggered.
+// If the state is unknown or the SwitchBot is in press mode, off function is always triggered.
+func (b *Bot) EnsureOff(wait bool) (bool, error) {
+	if s := b.state; s != nil && s.StateMode && !s.On {
+		b.logger.Info("skipped redundant command", "command", "off")
+		return false, nil
+	}
+	if err := b.Off(wait); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // Down triggers down function for the SwitchBot.
 func (b *Bot) Down(wait bool) error {
-	var cmd []byte
-	if b.encrypted() {
-		cmd = append(append([]byte{0x57, 0x11}, b.pw...), []byte{0x03}...)
-	} else {
-		cmd = []byte{0x57, 0x01, 0x03}
-	}
-	_, err := b.trigger(cmd, wait)
+	_, err := b.act("down", botDown, wait)
 	return err
 }
 
 // Up triggers down function for the SwitchBot.
 func (b *Bot) Up(wait bool) error {
-	var cmd []byte
-	if b.encrypted() {
-		cmd = append(append([]byte{0x57, 0x11}, b.pw...), []byte{0x04}...)
-	} else {
-		cmd = []byte{0x57, 0x01, 0x04}
-	}
-	_, err := b.trigger(cmd, wait)
+	_, err := b.act("up", botUp, wait)
 	return err
 }
 
 // GetInfo retrieves bot's settings.
 func (b *Bot) GetInfo() (*BotInfo, error) {
-	var cmd []byte
-	if len(b.pw) != 0 {
-		cmd = append([]byte{0x57, 0x12}, b.pw...)
-	} else {
-		cmd = []byte{0x57, 0x02}
-	}
-
-	res, err := b.trigger(cmd, true)
+	res, err := b.trigger(botInfo, true)
 	if err != nil {
 		return nil, err
 	}
-	return NewBotInfoWithRawInfo(res), nil
+	info := NewBotInfoWithRawInfo(res)
+	b.battery.record(b.logger, b.Addr, "Bot", BatterySourceInfo, info)
+	return info, nil
 }
 
 // GetTimers retrieves bot's timer settings.
@@ -143,13 +159,7 @@ func (b *Bot) GetTimers(cnt int) ([]*Timer, error) {
 	ret := []*Timer{}
 
 	for i := 0; i < cnt; i++ {
-		var cmd []byte
-		if len(b.pw) != 0 {
-			cmd = append([]byte{0x57, 0x18}, b.pw...)
-		} else {
-			cmd = []byte{0x57, 0x08}
-		}
-		cmd = append(cmd, []byte{byte(i*16 + 3)}...)
+		cmd := []byte{0x57, 0x08, byte(i*16 + 3)}
 		r, err := b.trigger(cmd, true)
 		if err != nil {
 			return ret, err
@@ -159,35 +169,3 @@ func (b *Bot) GetTimers(cnt int) ([]*Timer, error) {
 	}
 	return ret, nil
 }
-
-func (b *Bot) encrypted() bool {
-	return len(b.pw) != 0
-}
-
-// trigger executes write characteristics againt SwitchBot.
-// response []byte represents following status.
-// []byte{1}: trigger success.
-// []byte{0}: trigger failure.
-func (b *Bot) trigger(cmd []byte, wait bool) ([]byte, error) {
-	if wait && !b.subscribed {
-		if err := b.Subscribe(); err != nil {
-			return []byte{0}, err
-		}
-	}
-
-	_, err := b.cmdchar.WriteWithoutResponse(cmd)
-	if err != nil {
-		return []byte{0}, err
-	}
-
-	if !wait {
-		return []byte{1}, nil
-	}
-
-	res := <-b.subsque
-	if res[0] != byte(1) {
-		return res, errors.New("failed to send command to SwitchBot")
-	}
-
-	return res, nil
-}
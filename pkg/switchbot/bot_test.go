@@ -0,0 +1,76 @@
+package switchbot
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBotEnsureOnOff(t *testing.T) {
+	tests := []struct {
+		name        string
+		svcData     []byte
+		ensureOn    bool
+		wantChanged bool
+	}{
+		{"already on", []byte{0x48, 0x80, 0xcf}, true, false},
+		{"turn on", []byte{0x48, 0xc0, 0xcf}, true, true},
+		{"already off", []byte{0x48, 0xc0, 0xcf}, false, false},
+		{"turn off", []byte{0x48, 0x80, 0xcf}, false, true},
+		{"press mode", []byte{0x48, 0x00, 0xcf}, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ft := newFakeBotTransport()
+			ft.adv.ServiceData = tt.svcData
+			useTestTransport(t, ft)
+
+			bot, err := Connect(context.Background(), "11:22:33:44:55:66", time.Second)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var changed bool
+			if tt.ensureOn {
+				changed, err = bot.EnsureOn(true)
+			} else {
+				changed, err = bot.EnsureOff(true)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("expected changed %t, got %t", tt.wantChanged, changed)
+			}
+			if got := len(ft.written()); (got == 1) != tt.wantChanged {
+				t.Errorf("unexpected %d writes", got)
+			}
+		})
+	}
+}
+
+func TestBotEnsureOnTracksState(t *testing.T) {
+	ft := newFakeBotTransport()
+	ft.adv.ServiceData = []byte{0x48, 0xc0, 0xcf}
+	useTestTransport(t, ft)
+
+	bot, err := Connect(context.Background(), "11:22:33:44:55:66", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := bot.EnsureOn(true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writes := ft.written()
+	if len(writes) != 1 || !bytes.Equal(writes[0], []byte{0x57, 0x01, 0x01}) {
+		t.Errorf("expected single on command, got %x", writes)
+	}
+	if !bot.State().On {
+		t.Error("expected state to be on")
+	}
+}
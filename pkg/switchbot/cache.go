@@ -0,0 +1,204 @@
+package switchbot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultDeviceCacheTTL is duration which DeviceCache remembers SwitchBot for by default.
+const DefaultDeviceCacheTTL = 24 * time.Hour
+
+// CachedDevice represents SwitchBot remembered by DeviceCache.
+type CachedDevice struct {
+	Addr        Address   `json:"addr"`
+	AddressType string    `json:"address_type,omitempty"`
+	LocalName   string    `json:"local_name,omitempty"`
+	ServiceData HexBytes  `json:"service_data,omitempty"`
+	RSSI        int16     `json:"rssi"`
+	LastSeen    time.Time `json:"last_seen"`
+}
+
+// DeviceCacheStats represents counters of DeviceCache lookups by Connect functions.
+type DeviceCacheStats struct {
+	// Hits is count of lookups which found SwitchBot.
+	Hits int `json:"hits"`
+	// Misses is count of lookups which did not find SwitchBot or found expired one.
+	Misses int `json:"misses"`
+	// Fallbacks is count of direct connections which failed and fell back to scan.
+	Fallbacks int `json:"fallbacks"`
+}
+
+// DeviceCache remembers recently seen SwitchBots, so that Connect functions connect
+// to them directly before scanning. DeviceCache is safe for concurrent use.
+type DeviceCache struct {
+	mu      sync.Mutex
+	path    string
+	ttl     time.Duration
+	devices map[Address]*CachedDevice
+	stats   DeviceCacheStats
+	now     func() time.Time
+}
+
+// NewDeviceCache initializes in-memory DeviceCache which remembers SwitchBot for ttl.
+// If ttl is zero, DefaultDeviceCacheTTL is used.
+func NewDeviceCache(ttl time.Duration) *DeviceCache {
+	if ttl <= 0 {
+		ttl = DefaultDeviceCacheTTL
+	}
+	return &DeviceCache{
+		ttl:     ttl,
+		devices: make(map[Address]*CachedDevice),
+		now:     time.Now,
+	}
+}
+
+// OpenDeviceCache initializes DeviceCache with SwitchBots stored as JSON at path.
+// If the file does not exist, the cache is empty. Save writes the cache back to path.
+func OpenDeviceCache(path string, ttl time.Duration) (*DeviceCache, error) {
+	c := NewDeviceCache(ttl)
+	c.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var devices []*CachedDevice
+	if err := json.Unmarshal(data, &devices); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, d := range devices {
+		c.devices[d.Addr] = d
+	}
+	return c, nil
+}
+
+// Save writes unexpired SwitchBots as JSON to path given to OpenDeviceCache.
+// Parent directories are created if needed. Save of in-memory cache does nothing.
+func (c *DeviceCache) Save() error {
+	if c.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(c.Devices(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, append(data, '\n'), 0o644)
+}
+
+// Devices returns unexpired SwitchBots sorted by address.
+func (c *DeviceCache) Devices() []*CachedDevice {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ret := make([]*CachedDevice, 0, len(c.devices))
+	for _, d := range c.devices {
+		if !c.expired(d) {
+			dd := *d
+			ret = append(ret, &dd)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Addr < ret[j].Addr
+	})
+	return ret
+}
+
+// Get returns SwitchBot of addr unless it is unknown or expired.
+// Get is counted as hit or miss of Stats.
+func (c *DeviceCache) Get(addr Address) (*CachedDevice, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	d, ok := c.devices[addr]
+	if !ok || c.expired(d) {
+		c.stats.Misses++
+		return nil, false
+	}
+	c.stats.Hits++
+	dd := *d
+	return &dd, true
+}
+
+// Put remembers SwitchBot which sent adv.
+func (c *DeviceCache) Put(adv *Advertisement) {
+	addr, err := ParseAddress(adv.Addr)
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.devices[addr] = &CachedDevice{
+		Addr:        addr,
+		AddressType: adv.AddressType,
+		LocalName:   adv.LocalName,
+		ServiceData: append(HexBytes{}, adv.ServiceData...),
+		RSSI:        adv.RSSI,
+		LastSeen:    c.now(),
+	}
+}
+
+// Remove forgets SwitchBot of addr.
+func (c *DeviceCache) Remove(addr Address) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.devices, addr)
+}
+
+// Stats returns counters of lookups.
+func (c *DeviceCache) Stats() DeviceCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats
+}
+
+// fallback forgets SwitchBot of addr which could not be connected directly.
+func (c *DeviceCache) fallback(addr Address) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.devices, addr)
+	c.stats.Fallbacks++
+}
+
+func (c *DeviceCache) expired(d *CachedDevice) bool {
+	return c.now().Sub(d.LastSeen) > c.ttl
+}
+
+// advertisement returns Advertisement which d was remembered with.
+func (d *CachedDevice) advertisement() *Advertisement {
+	return &Advertisement{
+		Addr:        string(d.Addr),
+		AddressType: d.AddressType,
+		RSSI:        d.RSSI,
+		LocalName:   d.LocalName,
+		ServiceData: d.ServiceData,
+		cached:      true,
+	}
+}
+
+// SetDeviceCache sets DeviceCache used by Scan and Connect functions.
+// If c is nil, the cache is disabled. The cache is disabled by default.
+func SetDeviceCache(c *DeviceCache) {
+	defaultClient.configure(WithDeviceCache(c))
+}
+
+// CurrentDeviceCache returns DeviceCache set by SetDeviceCache.
+func CurrentDeviceCache() *DeviceCache {
+	return defaultClient.current().cache
+}
@@ -0,0 +1,171 @@
+package switchbot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"tinygo.org/x/bluetooth"
+)
+
+func TestDeviceCache(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	c := NewDeviceCache(time.Hour)
+	c.now = func() time.Time { return now }
+
+	c.Put(&Advertisement{Addr: "aa:bb:cc:dd:ee:ff", AddressType: AddressTypeRandom, RSSI: -60, ServiceData: []byte{0x48}})
+	d, ok := c.Get("AA:BB:CC:DD:EE:FF")
+	if !ok {
+		t.Fatal("expected cached device to be found")
+	}
+	if d.AddressType != AddressTypeRandom || d.RSSI != -60 || !d.LastSeen.Equal(now) {
+		t.Errorf("unexpected cached device %+v", d)
+	}
+	if _, ok := c.Get("11:22:33:44:55:66"); ok {
+		t.Error("expected unknown device to be missed")
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, ok := c.Get("AA:BB:CC:DD:EE:FF"); ok {
+		t.Error("expected expired device to be missed")
+	}
+	if got := c.Stats(); got != (DeviceCacheStats{Hits: 1, Misses: 2}) {
+		t.Errorf("unexpected stats %+v", got)
+	}
+}
+
+func TestDeviceCacheSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "switchbot", "devices.json")
+	c, err := OpenDeviceCache(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Put(&Advertisement{Addr: "11:22:33:44:55:66", AddressType: AddressTypePublic, LocalName: "WoHand", RSSI: -70, ServiceData: []byte{0x48, 0x90}})
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := OpenDeviceCache(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	devices := loaded.Devices()
+	if len(devices) != 1 {
+		t.Fatalf("expected 1 device, got %d", len(devices))
+	}
+	d := devices[0]
+	if d.Addr != "11:22:33:44:55:66" || d.LocalName != "WoHand" || !bytes.Equal(d.ServiceData, []byte{0x48, 0x90}) {
+		t.Errorf("unexpected loaded device %+v", d)
+	}
+}
+
+func TestConnectWithDeviceCache(t *testing.T) {
+	ft := newFakeBotTransport()
+	ft.adv.AddressType = AddressTypeRandom
+	cache := NewDeviceCache(0)
+	var buf bytes.Buffer
+	c := NewClient(
+		WithTransport(ft),
+		WithDeviceCache(cache),
+		WithScanTimeout(time.Second),
+		WithLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+	)
+	defer c.Close()
+
+	for i := 0; i < 2; i++ {
+		bot, err := c.Connect(context.Background(), "11:22:33:44:55:66")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 1 && bot.State() != nil {
+			t.Error("expected state of cached device to be unknown")
+		}
+		bot.Disconnect()
+	}
+
+	if n := ft.scanCount(); n != 1 {
+		t.Errorf("expected only the first connection to scan, scanned %d times", n)
+	}
+	if len(ft.directs) != 1 || ft.directs[0] != AddressTypeRandom {
+		t.Errorf("expected direct connection to random address, got %v", ft.directs)
+	}
+	if got := cache.Stats(); got != (DeviceCacheStats{Hits: 1, Misses: 1}) {
+		t.Errorf("unexpected stats %+v", got)
+	}
+	if !strings.Contains(buf.String(), "device cache miss") || !strings.Contains(buf.String(), "device cache hit") {
+		t.Errorf("expected cache hit and miss to be logged, got %q", buf.String())
+	}
+}
+
+func TestConnectWithDeviceCacheFallback(t *testing.T) {
+	ft := newFakeBotTransport()
+	ft.directErr = errors.New("device not available")
+	cache := NewDeviceCache(0)
+	cache.Put(ft.adv)
+
+	bot, err := ConnectWithOptions(context.Background(), "11:22:33:44:55:66",
+		WithTransport(ft), WithDeviceCache(cache), WithScanTimeout(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bot.Disconnect()
+
+	if n := ft.scanCount(); n != 1 {
+		t.Errorf("expected failed direct connection to fall back to scan, scanned %d times", n)
+	}
+	if got := cache.Stats(); got != (DeviceCacheStats{Hits: 1, Fallbacks: 1}) {
+		t.Errorf("unexpected stats %+v", got)
+	}
+	if len(cache.Devices()) != 1 {
+		t.Error("expected device found by scan to be cached again")
+	}
+}
+
+// enableTransport fails connection until Enable is called, like adapter of Linux.
+type enableTransport struct {
+	*fakeTransport
+	enabled bool
+}
+
+func (t *enableTransport) Enable() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.enabled = true
+	return nil
+}
+
+func (t *enableTransport) ConnectDirect(addr, addrType string, params bluetooth.ConnectionParams) (Peripheral, error) {
+	t.mu.Lock()
+	enabled := t.enabled
+	t.mu.Unlock()
+
+	if !enabled {
+		return nil, errors.New("adapter is not enabled")
+	}
+	return t.fakeTransport.ConnectDirect(addr, addrType, params)
+}
+
+func TestConnectWithDeviceCacheEnables(t *testing.T) {
+	et := &enableTransport{fakeTransport: newFakeBotTransport()}
+	cache := NewDeviceCache(0)
+	cache.Put(et.adv)
+
+	bot, err := ConnectWithOptions(context.Background(), "11:22:33:44:55:66",
+		WithTransport(et), WithDeviceCache(cache), WithScanTimeout(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bot.Disconnect()
+
+	if n := et.scanCount(); n != 0 {
+		t.Errorf("expected cached device to be connected directly, scanned %d times", n)
+	}
+	if got := cache.Stats(); got != (DeviceCacheStats{Hits: 1}) {
+		t.Errorf("unexpected stats %+v", got)
+	}
+}
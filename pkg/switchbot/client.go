@@ -0,0 +1,209 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"tinygo.org/x/bluetooth"
+)
+
+// Client scans and connects SwitchBots with its own adapters, transport, logger, device cache,
+// aliases, battery recorder and options.
+// Clients are independent of each other, so that each of them can be used in parallel
+// and closed separately. Package functions such as Scan and Connect use the default client
+// configured by SetTransport, SetAdapters, SetLogger, SetTimeouts, SetDeviceCache, SetAliases
+// and SetBatteryRecorder.
+type Client struct {
+	mu     sync.Mutex
+	base   options
+	closed bool
+	// peripherals are connected peripherals which are disconnected by Close.
+	peripherals map[Peripheral]struct{}
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+var defaultClient = NewClient()
+
+// NewClient initializes Client with opts. Transport built on bluetooth.DefaultAdapter
+// is used unless WithTransport, WithAdapter or WithAdapters is given.
+// Options given to each method are applied after opts.
+func NewClient(opts ...Option) *Client {
+	o := options{
+		transport: NewAdapterTransport(bluetooth.DefaultAdapter),
+		logger:    newDiscardLogger(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Client{
+		base:        o,
+		peripherals: make(map[Peripheral]struct{}),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// Close cancels scans and connections in progress and disconnects every SwitchBot
+// connected by the client. Methods called after Close return ErrClientClosed.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	ps := c.peripherals
+	c.peripherals = nil
+	c.mu.Unlock()
+
+	c.cancel()
+	var errs []error
+	for p := range ps {
+		if err := p.Disconnect(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	c.base.logger.Debug("client closed", "disconnected", len(ps))
+	return errors.Join(errs...)
+}
+
+// Scan is ScanWithOptions with options of the client and opts.
+func (c *Client) Scan(ctx context.Context, callback func(res *ScanResult), opts ...Option) error {
+	o, err := c.options(opts)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	return scanWithOptions(ctx, callback, o)
+}
+
+// Connect connects to Bot filter by addr argument with opts.
+// It returns the same errors as Connect.
+func (c *Client) Connect(ctx context.Context, addr string, opts ...Option) (*Bot, error) {
+	res, cn, err := c.connect(ctx, addr, opts, "Bot")
+	if err != nil {
+		return nil, err
+	}
+	b := &Bot{Addr: res.Addr, conn: cn}
+	if res.cached {
+		// State remembered by device cache may be outdated.
+		return b, nil
+	}
+	if state, err := NewBotStateWithAdvertisement(res.ServiceData); err == nil {
+		b.state = state
+	}
+	return b, nil
+}
+
+// ConnectDevice connects to SwitchBot of any model filter by addr argument with opts.
+// It returns the same errors as ConnectDevice.
+func (c *Client) ConnectDevice(ctx context.Context, addr string, opts ...Option) (*Device, error) {
+	res, cn, err := c.connect(ctx, addr, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Device{Addr: res.Addr, Model: matchModel(res), conn: cn}, nil
+}
+
+// DiscoverGATT is DiscoverGATT with options of the client and opts.
+func (c *Client) DiscoverGATT(ctx context.Context, addr string, opts ...Option) ([]*GATTService, error) {
+	o, err := c.options(opts)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	return discoverGATTWithOptions(ctx, addr, o)
+}
+
+// connect connects to SwitchBot of models by options of the client and opts.
+// The connection is disconnected by Close unless it is disconnected before.
+func (c *Client) connect(ctx context.Context, addr string, opts []Option, models ...string) (*Advertisement, conn, error) {
+	o, err := c.options(opts)
+	if err != nil {
+		return nil, conn{}, err
+	}
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	res, cn, err := connect(ctx, addr, o, models...)
+	if err != nil {
+		return nil, conn{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		cn.Disconnect()
+		return nil, conn{}, ErrClientClosed
+	}
+	c.peripherals[cn.dev] = struct{}{}
+	cn.client = c
+	return res, cn, nil
+}
+
+// scanAddr is scanAddr with options of the client and opts.
+func (c *Client) scanAddr(ctx context.Context, addr string, opts []Option, models ...string) (*Advertisement, error) {
+	o, err := c.options(opts)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	return scanAddr(ctx, addr, o, models...)
+}
+
+// release forgets p which is disconnected.
+func (c *Client) release(p Peripheral) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.peripherals, p)
+}
+
+// options returns options of the client with opts applied.
+func (c *Client) options(opts []Option) (*options, error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, ErrClientClosed
+	}
+	o := c.base
+	c.mu.Unlock()
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &o, nil
+}
+
+// configure applies opts to options of the client.
+func (c *Client) configure(opts ...Option) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, opt := range opts {
+		opt(&c.base)
+	}
+}
+
+// current returns options of the client.
+func (c *Client) current() options {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.base
+}
+
+// context returns ctx which is also canceled by Close.
+func (c *Client) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(c.ctx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
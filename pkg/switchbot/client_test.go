@@ -0,0 +1,114 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestClientParallel(t *testing.T) {
+	for i := 0; i < 3; i++ {
+		i := i
+		t.Run(fmt.Sprintf("client%d", i), func(t *testing.T) {
+			t.Parallel()
+
+			ft := newFakeBotTransport()
+			c := NewClient(WithTransport(ft), WithScanTimeout(time.Second))
+			defer c.Close()
+
+			bot, err := c.Connect(context.Background(), "11:22:33:44:55:66")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for j := 0; j <= i; j++ {
+				if err := bot.Press(true); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := len(ft.written()); got != i+1 {
+				t.Errorf("expected %d commands to be written to own transport, got %d", i+1, got)
+			}
+		})
+	}
+}
+
+func TestClientClose(t *testing.T) {
+	ft := newFakeBotTransport()
+	c := NewClient(WithTransport(ft), WithScanTimeout(time.Second))
+
+	if _, err := c.Connect(context.Background(), "11:22:33:44:55:66"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !ft.isDisconnected() {
+		t.Error("expected Close to disconnect connected Bot")
+	}
+
+	if _, err := c.Connect(context.Background(), "11:22:33:44:55:66"); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("expected ErrClientClosed, got %v", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Errorf("expected second Close to succeed, got %v", err)
+	}
+}
+
+func TestClientCloseDisconnected(t *testing.T) {
+	ft := newFakeBotTransport()
+	c := NewClient(WithTransport(ft), WithScanTimeout(time.Second))
+
+	bot, err := c.Connect(context.Background(), "11:22:33:44:55:66")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bot.Disconnect()
+	if n := len(c.peripherals); n != 0 {
+		t.Errorf("expected disconnected Bot to be released, %d peripherals remain", n)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClientCloseCancelsScan(t *testing.T) {
+	c := NewClient(WithTransport(newFakeBotTransport()), WithScanTimeout(time.Minute))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Scan(context.Background(), func(res *ScanResult) {})
+	}()
+	time.Sleep(10 * time.Millisecond)
+	c.Close()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected Close to cancel Scan")
+	}
+}
+
+func TestClientSettings(t *testing.T) {
+	s := openTestBatteryStore(t)
+	ft := newFakeBotTransport()
+	c := NewClient(WithTransport(ft), WithScanTimeout(time.Second),
+		WithAliases(Aliases{"kitchen": "11:22:33:44:55:66"}), WithBatteryRecorder(s))
+	defer c.Close()
+
+	if _, err := c.Connect(context.Background(), "kitchen"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ResolveAddress("kitchen"); err == nil {
+		t.Error("expected aliases of client not to be set to default client")
+	}
+
+	readings, err := s.BatteryReadings("11:22:33:44:55:66", time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(readings) != 1 || readings[0].Source != BatterySourceAdvertisement {
+		t.Errorf("expected battery to be recorded by recorder of client, got %+v", readings)
+	}
+}
@@ -0,0 +1,233 @@
+package switchbot
+
+import (
+	"encoding/hex"
+	"log/slog"
+	"time"
+
+	"tinygo.org/x/bluetooth"
+)
+
+// conn represents GATT connection shared by every SwitchBot device.
+type conn struct {
+	dev Peripheral
+
+	subschar Characteristic
+	cmdchar  Characteristic
+
+	framer Framer
+
+	addr   string
+	logger *slog.Logger
+
+	subsque    chan []byte
+	subscribed bool
+
+	// responseTimeout is deadline to receive response of each command.
+	responseTimeout time.Duration
+
+	// battery records battery levels reported by the SwitchBot.
+	battery *batteryRecorder
+	// audit records actions executed against the SwitchBot.
+	audit *auditor
+
+	// client is Client which established the connection.
+	client *Client
+}
+
+func newConn(addr string) conn {
+	addr = normalizeAddr(addr)
+	o := defaultClient.current()
+	return conn{
+		addr:       addr,
+		logger:     o.logger.With("addr", addr),
+		battery:    o.battery,
+		audit:      o.auditor(),
+		subsque:    make(chan []byte, 1),
+		subscribed: false,
+
+		responseTimeout: o.timeouts.Response,
+	}
+}
+
+// Subscribe subscribes to device and waiting notification from SwitchBot.
+func (c *conn) Subscribe() error {
+	err := c.subschar.EnableNotifications(func(info []byte) {
+		select {
+		case c.subsque <- info:
+		default:
+			c.logger.Debug("dropped unexpected notification", "notification", hex.EncodeToString(info))
+		}
+	})
+	if err != nil {
+		c.logger.Warn("failed to subscribe notification", "error", err)
+		return err
+	}
+	c.logger.Debug("subscribed notification")
+	c.subscribed = true
+	return nil
+}
+
+// SetFramer sets Framer which converts commands and responses.
+// If framer is nil, commands are written to SwitchBot as they are.
+func (c *conn) SetFramer(framer Framer) {
+	c.framer = framer
+}
+
+// SetLogger sets logger which receives command and notification events.
+// If l is nil, logging is disabled.
+func (c *conn) SetLogger(l *slog.Logger) {
+	if l == nil {
+		l = newDiscardLogger()
+	}
+	c.logger = l.With("addr", c.addr)
+}
+
+// Disconnect  disconnects current SwitchBot connection.
+func (c *conn) Disconnect() error {
+	if c.dev == nil {
+		return nil
+	}
+	c.logger.Debug("disconnecting")
+	if c.client != nil {
+		c.client.release(c.dev)
+	}
+	return c.dev.Disconnect()
+}
+
+// discover discovers SwitchBot service and its command and notification characteristics.
+func (c *conn) discover() error {
+	srvcs, err := c.dev.DiscoverServices([]bluetooth.UUID{serviceUUID})
+	if err != nil {
+		return &DiscoveryError{Addr: c.addr, UUID: serviceUUID.String(), Err: err}
+	}
+	c.logger.Debug("discovered services", "count", len(srvcs))
+
+	var srvc Service
+	for _, s := range srvcs {
+		if s.UUID() == serviceUUID {
+			srvc = s
+			break
+		}
+	}
+	if srvc == nil {
+		return &DiscoveryError{Addr: c.addr, UUID: serviceUUID.String(), Err: ErrServiceNotFound}
+	}
+
+	if c.cmdchar, err = c.discoverCharacteristic(srvc, commandUUID); err != nil {
+		return err
+	}
+	if c.subschar, err = c.discoverCharacteristic(srvc, subscribeUUID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *conn) discoverCharacteristic(srvc Service, uuid bluetooth.UUID) (Characteristic, error) {
+	chars, err := srvc.DiscoverCharacteristics([]bluetooth.UUID{uuid})
+	if err != nil {
+		return nil, &DiscoveryError{Addr: c.addr, UUID: uuid.String(), Err: err}
+	}
+	for _, char := range chars {
+		if char.UUID() == uuid {
+			return char, nil
+		}
+	}
+	return nil, &DiscoveryError{Addr: c.addr, UUID: uuid.String(), Err: ErrCharacteristicNotFound}
+}
+
+// trigger frames cmd with Framer and executes write characteristics againt SwitchBot.
+// act triggers cmd of action and appends the action to audit recorder of the connection.
+func (c *conn) act(action string, cmd []byte, wait bool) ([]byte, error) {
+	start := time.Now()
+	res, err := c.trigger(cmd, wait)
+	c.audit.append(c.logger, c.addr, action, start, err)
+	return res, err
+}
+
+func (c *conn) trigger(cmd []byte, wait bool) ([]byte, error) {
+	if c.framer == nil {
+		return c.write(cmd, wait)
+	}
+
+	if hs, ok := c.framer.(Handshaker); ok {
+		send := func(cmd []byte) ([]byte, error) {
+			return c.write(cmd, true)
+		}
+		if err := hs.Handshake(send); err != nil {
+			c.logger.Warn("handshake failed", "error", err)
+			return []byte{0}, err
+		}
+	}
+
+	fcmd, err := c.framer.Frame(cmd)
+	if err != nil {
+		return []byte{0}, err
+	}
+
+	res, err := c.write(fcmd, wait)
+	if err != nil || !wait {
+		return res, err
+	}
+	return c.framer.Unframe(res)
+}
+
+// write executes write characteristics againt SwitchBot.
+// response []byte represents following status.
+// []byte{1}: trigger success.
+// []byte{0}: trigger failure.
+func (c *conn) write(cmd []byte, wait bool) ([]byte, error) {
+	if wait && !c.subscribed {
+		if err := c.Subscribe(); err != nil {
+			return []byte{0}, err
+		}
+	}
+
+	// Drop stale notification which is not a response of cmd.
+	select {
+	case <-c.subsque:
+	default:
+	}
+
+	start := time.Now()
+	_, err := c.cmdchar.WriteWithoutResponse(cmd)
+	if err != nil {
+		c.logger.Warn("failed to write command", "opcode", opcode(cmd), "command", hex.EncodeToString(cmd), "error", err)
+		return []byte{0}, err
+	}
+	c.logger.Debug("wrote command", "opcode", opcode(cmd), "command", hex.EncodeToString(cmd))
+
+	if !wait {
+		return []byte{1}, nil
+	}
+
+	var timeout <-chan time.Time
+	if c.responseTimeout > 0 {
+		t := time.NewTimer(c.responseTimeout)
+		defer t.Stop()
+		timeout = t.C
+	}
+
+	var res []byte
+	select {
+	case res = <-c.subsque:
+	case <-timeout:
+		err := &TimeoutError{Addr: c.addr, Phase: PhaseResponse, Timeout: c.responseTimeout}
+		c.logger.Warn("failed to receive response", "opcode", opcode(cmd), "error", err)
+		return []byte{0}, err
+	}
+	c.logger.Debug("received notification", "opcode", opcode(cmd), "response", hex.EncodeToString(res), "duration", time.Since(start))
+	if len(res) == 0 || res[0] != byte(1) {
+		return res, &CommandError{Response: res}
+	}
+
+	return res, nil
+}
+
+// opcode returns command byte following 0x57 header as hex string.
+func opcode(cmd []byte) string {
+	if len(cmd) < 2 {
+		return ""
+	}
+	return hex.EncodeToString(cmd[1:2])
+}
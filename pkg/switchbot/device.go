@@ -0,0 +1,57 @@
+package switchbot
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Device represents SwitchBot device of any registered model.
+type Device struct {
+	Addr  string
+	Model *Model
+
+	conn
+}
+
+// NewDevice initializes device object with model.
+func NewDevice(addr string, model *Model) *Device {
+	return &Device{Addr: normalizeAddr(addr), Model: model, conn: newConn(addr)}
+}
+
+// ConnectDevice connects to SwitchBot filter by addr argument.
+// Model of the device is identified from its advertisement. If the model is not
+// registered, Model of returned device is nil.
+// If connection failed within timeout, ConnectDevice returns error.
+func ConnectDevice(ctx context.Context, addr string, timeout time.Duration) (*Device, error) {
+	return ConnectDeviceWithOptions(ctx, addr, WithScanTimeout(timeout))
+}
+
+// ConnectDeviceWithOptions connects to SwitchBot of any model filter by addr argument with opts.
+// It returns the same errors as ConnectDevice.
+func ConnectDeviceWithOptions(ctx context.Context, addr string, opts ...Option) (*Device, error) {
+	return defaultClient.ConnectDevice(ctx, addr, opts...)
+}
+
+// Exec executes command registered to the model by name.
+func (d *Device) Exec(name string, wait bool) ([]byte, error) {
+	if d.Model == nil {
+		return nil, fmt.Errorf("model of %s is unknown", d.Addr)
+	}
+	cmd, ok := d.Model.Commands[name]
+	if !ok {
+		return nil, fmt.Errorf("%s does not support command %q", d.Model.Name, name)
+	}
+	return d.act(name, cmd, wait)
+}
+
+// Send writes cmd to the device and returns notification if wait is true.
+// If wait is false, nil is returned since nothing is read back.
+// cmd is framed with Framer set by SetFramer.
+func (d *Device) Send(cmd []byte, wait bool) ([]byte, error) {
+	res, err := d.act(fmt.Sprintf("raw %x", cmd), cmd, wait)
+	if !wait {
+		return nil, err
+	}
+	return res, err
+}
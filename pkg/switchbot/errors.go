@@ -0,0 +1,76 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrServiceNotFound is returned when connected peripheral has no SwitchBot service.
+	ErrServiceNotFound = errors.New("SwitchBot service is not found")
+	// ErrCharacteristicNotFound is returned when SwitchBot service lacks command or
+	// notification characteristic.
+	ErrCharacteristicNotFound = errors.New("SwitchBot characteristic is not found")
+	// ErrClientClosed is returned when Client is used after Close.
+	ErrClientClosed = errors.New("client is closed")
+)
+
+// DiscoveryError represents failure of GATT discovery against SwitchBot.
+// Err is ErrServiceNotFound, ErrCharacteristicNotFound or error of Transport.
+type DiscoveryError struct {
+	Addr string
+	// UUID is UUID of service or characteristic which failed to be discovered.
+	UUID string
+	Err  error
+}
+
+func (e *DiscoveryError) Error() string {
+	return fmt.Sprintf("failed to discover %s of %s: %s", e.UUID, e.Addr, e.Err.Error())
+}
+
+func (e *DiscoveryError) Unwrap() error {
+	return e.Err
+}
+
+// ModelMismatchError is returned when found SwitchBot is not a model expected by caller,
+// such as connecting Plug Mini with ConnectBulb.
+type ModelMismatchError struct {
+	Addr     string
+	Model    string
+	Expected []string
+}
+
+func (e *ModelMismatchError) Error() string {
+	return fmt.Sprintf("%s is %s, not %s", e.Addr, e.Model, strings.Join(e.Expected, " or "))
+}
+
+// CommandError is returned when SwitchBot responds to a command with failure status,
+// such as a command without correct password.
+type CommandError struct {
+	// Response is the response from SwitchBot. The first byte is the status.
+	Response []byte
+}
+
+func (e *CommandError) Error() string {
+	return fmt.Sprintf("failed to send command to SwitchBot: response %x", e.Response)
+}
+
+// TimeoutError is returned when a phase of connection or command does not finish
+// within its deadline. It wraps context.DeadlineExceeded.
+type TimeoutError struct {
+	Addr string
+	// Phase is PhaseScan, PhaseConnect, PhaseDiscovery or PhaseResponse.
+	Phase   string
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s of %s timed out after %s", e.Phase, e.Addr, e.Timeout)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
@@ -0,0 +1,198 @@
+package switchbot
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"tinygo.org/x/bluetooth"
+)
+
+// fakeTransport is in-memory GATT stack for tests.
+// Services maps service UUID to characteristic UUIDs of every peripheral.
+type fakeTransport struct {
+	adv      *Advertisement
+	others   []*Advertisement
+	services map[bluetooth.UUID][]bluetooth.UUID
+
+	mu           sync.Mutex
+	stop         chan struct{}
+	disconnected bool
+	writes       [][]byte
+	notify       func(buf []byte)
+
+	// onWrite is called with written command, such as to update advertisement.
+	onWrite func(p []byte)
+
+	// connectDelay and discoverDelay delay Connect and DiscoverServices.
+	connectDelay  time.Duration
+	discoverDelay time.Duration
+	// silent suppresses notification of responses.
+	silent bool
+
+	// scans counts Scan calls and directs records address types of ConnectDirect calls.
+	scans   int
+	directs []string
+	// directErr is returned by ConnectDirect.
+	directErr error
+}
+
+func newFakeTransport(adv *Advertisement, services map[bluetooth.UUID][]bluetooth.UUID) *fakeTransport {
+	return &fakeTransport{adv: adv, services: services}
+}
+
+// newFakeBotTransport returns fakeTransport of Bot which has complete SwitchBot service.
+func newFakeBotTransport() *fakeTransport {
+	return newFakeTransport(
+		&Advertisement{Addr: "11:22:33:44:55:66", LocalName: "WoHand", ServiceData: []byte{0x48, 0x90, 0xcf}},
+		map[bluetooth.UUID][]bluetooth.UUID{serviceUUID: {commandUUID, subscribeUUID}},
+	)
+}
+
+func (t *fakeTransport) Enable() error {
+	return nil
+}
+
+func (t *fakeTransport) Scan(callback func(adv *Advertisement)) error {
+	stop := make(chan struct{})
+	t.mu.Lock()
+	t.stop = stop
+	t.scans++
+	t.mu.Unlock()
+
+	callback(t.adv)
+	for _, adv := range t.others {
+		callback(adv)
+	}
+	<-stop
+	return nil
+}
+
+func (t *fakeTransport) StopScan() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.stop != nil {
+		close(t.stop)
+		t.stop = nil
+	}
+	return nil
+}
+
+func (t *fakeTransport) Connect(addr string) (Peripheral, error) {
+	time.Sleep(t.connectDelay)
+	for _, adv := range append([]*Advertisement{t.adv}, t.others...) {
+		if adv.Addr == addr {
+			return &fakePeripheral{t: t}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown address %s", addr)
+}
+
+func (t *fakeTransport) ConnectDirect(addr, addrType string, params bluetooth.ConnectionParams) (Peripheral, error) {
+	t.mu.Lock()
+	t.directs = append(t.directs, addrType)
+	err := t.directErr
+	t.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+	return t.Connect(addr)
+}
+
+func (t *fakeTransport) scanCount() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.scans
+}
+
+func (t *fakeTransport) written() [][]byte {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([][]byte{}, t.writes...)
+}
+
+func (t *fakeTransport) isDisconnected() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.disconnected
+}
+
+type fakePeripheral struct {
+	t *fakeTransport
+}
+
+func (p *fakePeripheral) DiscoverServices(uuids []bluetooth.UUID) ([]Service, error) {
+	time.Sleep(p.t.discoverDelay)
+	var ret []Service
+	for uuid, chars := range p.t.services {
+		if containsUUID(uuids, uuid) {
+			ret = append(ret, &fakeService{t: p.t, uuid: uuid, chars: chars})
+		}
+	}
+	return ret, nil
+}
+
+func (p *fakePeripheral) Disconnect() error {
+	p.t.mu.Lock()
+	defer p.t.mu.Unlock()
+	p.t.disconnected = true
+	return nil
+}
+
+type fakeService struct {
+	t     *fakeTransport
+	uuid  bluetooth.UUID
+	chars []bluetooth.UUID
+}
+
+func (s *fakeService) UUID() bluetooth.UUID {
+	return s.uuid
+}
+
+func (s *fakeService) DiscoverCharacteristics(uuids []bluetooth.UUID) ([]Characteristic, error) {
+	var ret []Characteristic
+	for _, uuid := range s.chars {
+		if containsUUID(uuids, uuid) {
+			ret = append(ret, &fakeCharacteristic{t: s.t, uuid: uuid})
+		}
+	}
+	return ret, nil
+}
+
+// fakeCharacteristic records written commands and notifies success response.
+type fakeCharacteristic struct {
+	t    *fakeTransport
+	uuid bluetooth.UUID
+}
+
+func (c *fakeCharacteristic) UUID() bluetooth.UUID {
+	return c.uuid
+}
+
+func (c *fakeCharacteristic) WriteWithoutResponse(p []byte) (int, error) {
+	c.t.mu.Lock()
+	defer c.t.mu.Unlock()
+
+	c.t.writes = append(c.t.writes, append([]byte{}, p...))
+	if c.t.onWrite != nil {
+		c.t.onWrite(p)
+	}
+	if notify := c.t.notify; notify != nil && !c.t.silent {
+		go notify([]byte{0x01})
+	}
+	return len(p), nil
+}
+
+func (c *fakeCharacteristic) EnableNotifications(callback func(buf []byte)) error {
+	c.t.mu.Lock()
+	defer c.t.mu.Unlock()
+
+	c.t.notify = callback
+	return nil
+}
+
+func (c *fakeCharacteristic) Read(data []byte) (int, error) {
+	return 0, nil
+}
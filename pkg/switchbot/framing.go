@@ -0,0 +1,137 @@
+package switchbot
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
+)
+
+// Framer converts command to bytes written to SwitchBot,
+// and converts notification from SwitchBot back to response.
+type Framer interface {
+	// Frame converts command which starts with 0x57 header to bytes written to SwitchBot.
+	Frame(cmd []byte) ([]byte, error)
+	// Unframe converts notification from SwitchBot to response.
+	Unframe(res []byte) ([]byte, error)
+}
+
+// Handshaker is implemented by Framer which needs to establish session
+// before the first command is framed.
+type Handshaker interface {
+	// Handshake establishes session.
+	// send writes cmd to SwitchBot without framing and returns notification.
+	Handshake(send func(cmd []byte) ([]byte, error)) error
+}
+
+// PasswordFramer frames command with CRC32 of the password.
+// This is the legacy framing used by SwitchBot Bot, Plug Mini and so on.
+type PasswordFramer struct {
+	crc []byte
+}
+
+// NewPasswordFramer initializes PasswordFramer with password.
+func NewPasswordFramer(pw string) *PasswordFramer {
+	crc := crc32.ChecksumIEEE([]byte(pw))
+	bs := make([]byte, 4)
+	binary.BigEndian.PutUint32(bs[0:], crc)
+	return &PasswordFramer{crc: bs}
+}
+
+// Frame sets password flag to command byte and inserts CRC32 of the password after it.
+func (f *PasswordFramer) Frame(cmd []byte) ([]byte, error) {
+	if len(cmd) < 2 {
+		return nil, fmt.Errorf("command is too short: %x", cmd)
+	}
+	ret := []byte{cmd[0], cmd[1] | 0x10}
+	ret = append(ret, f.crc...)
+	return append(ret, cmd[2:]...), nil
+}
+
+// Unframe returns res as it is.
+func (f *PasswordFramer) Unframe(res []byte) ([]byte, error) {
+	return res, nil
+}
+
+// EncryptedFramer frames command with AES-CTR encryption.
+// This is the framing used by SwitchBot Lock, Lock Pro and so on.
+// IV of the session is retrieved from SwitchBot by Handshake.
+type EncryptedFramer struct {
+	keyID byte
+	block cipher.Block
+	iv    []byte
+}
+
+// NewEncryptedFramer initializes EncryptedFramer with key ID and encryption key.
+// Both keyID and key are hex encoded string, keyID is 1 byte and key is 16 bytes.
+func NewEncryptedFramer(keyID, key string) (*EncryptedFramer, error) {
+	id, err := hex.DecodeString(keyID)
+	if err != nil || len(id) != 1 {
+		return nil, fmt.Errorf("key ID must be 1 byte hex string: %q", keyID)
+	}
+	k, err := hex.DecodeString(key)
+	if err != nil || len(k) != aes.BlockSize {
+		return nil, fmt.Errorf("key must be %d bytes hex string", aes.BlockSize)
+	}
+	block, err := aes.NewCipher(k)
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptedFramer{keyID: id[0], block: block}, nil
+}
+
+// Handshake retrieves IV of the session from SwitchBot.
+// IV request is sent unencrypted, with 3 zero bytes in place of key ID and IV.
+func (f *EncryptedFramer) Handshake(send func(cmd []byte) ([]byte, error)) error {
+	if f.iv != nil {
+		return nil
+	}
+	res, err := send([]byte{0x57, 0x00, 0x00, 0x00, 0x0f, 0x21, 0x03, f.keyID})
+	if err != nil {
+		return err
+	}
+	return f.SetIV(res)
+}
+
+// SetIV sets IV of the session from response of IV request.
+func (f *EncryptedFramer) SetIV(res []byte) error {
+	if len(res) < 4+aes.BlockSize {
+		return fmt.Errorf("IV response is too short: %x", res)
+	}
+	f.iv = append([]byte{}, res[4:4+aes.BlockSize]...)
+	return nil
+}
+
+// Frame encrypts command except for 0x57 header,
+// and prepends key ID and first 2 bytes of IV to it.
+func (f *EncryptedFramer) Frame(cmd []byte) ([]byte, error) {
+	if f.iv == nil {
+		return nil, errors.New("encryption session is not established")
+	}
+	if len(cmd) < 2 {
+		return nil, fmt.Errorf("command is too short: %x", cmd)
+	}
+	ret := []byte{cmd[0], f.keyID}
+	ret = append(ret, f.iv[0:2]...)
+	return append(ret, f.crypt(cmd[1:])...), nil
+}
+
+// Unframe decrypts response except for status byte.
+func (f *EncryptedFramer) Unframe(res []byte) ([]byte, error) {
+	if f.iv == nil {
+		return nil, errors.New("encryption session is not established")
+	}
+	if len(res) < 4 {
+		return res, nil
+	}
+	return append([]byte{res[0]}, f.crypt(res[4:])...), nil
+}
+
+func (f *EncryptedFramer) crypt(src []byte) []byte {
+	dst := make([]byte, len(src))
+	cipher.NewCTR(f.block, f.iv).XORKeyStream(dst, src)
+	return dst
+}
@@ -0,0 +1,130 @@
+package switchbot
+
+import (
+	"bytes"
+	"testing"
+)
+
+var (
+	testKeyID = "2a"
+	testKey   = "000102030405060708090a0b0c0d0e0f"
+	testIV    = []byte{0xf0, 0xf1, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7, 0xf8, 0xf9, 0xfa, 0xfb, 0xfc, 0xfd, 0xfe, 0xff}
+)
+
+func newTestEncryptedFramer(t *testing.T) *EncryptedFramer {
+	f, err := NewEncryptedFramer(testKeyID, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := append([]byte{0x01, 0x00, 0x00, 0x00}, testIV...)
+	send := func(cmd []byte) ([]byte, error) {
+		// Same as COMMAND_GET_CK_IV of pySwitchBot sent with key ID 2a.
+		want := []byte{0x57, 0x00, 0x00, 0x00, 0x0f, 0x21, 0x03, 0x2a}
+		if !bytes.Equal(cmd, want) {
+			t.Errorf("IV request expected %x, got %x", want, cmd)
+		}
+		return res, nil
+	}
+	if err := f.Handshake(send); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestPasswordFramerFrame(t *testing.T) {
+	tests := []struct {
+		cmd  []byte
+		want []byte
+	}{
+		{
+			cmd:  []byte{0x57, 0x01},
+			want: []byte{0x57, 0x11, 0x5c, 0xa2, 0xe8, 0xe5},
+		},
+		{
+			cmd:  []byte{0x57, 0x01, 0x02},
+			want: []byte{0x57, 0x11, 0x5c, 0xa2, 0xe8, 0xe5, 0x02},
+		},
+		{
+			cmd:  []byte{0x57, 0x08, 0x13},
+			want: []byte{0x57, 0x18, 0x5c, 0xa2, 0xe8, 0xe5, 0x13},
+		},
+	}
+
+	f := NewPasswordFramer("secret")
+	for _, tt := range tests {
+		got, err := f.Frame(tt.cmd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("Frame(%x) expected %x, got %x", tt.cmd, tt.want, got)
+		}
+	}
+}
+
+func TestEncryptedFramerFrame(t *testing.T) {
+	tests := []struct {
+		cmd  []byte
+		want []byte
+	}{
+		{
+			cmd:  []byte{0x57, 0x0f, 0x4e, 0x01, 0x01, 0x10, 0x00},
+			want: []byte{0x57, 0x2a, 0xf0, 0xf1, 0x69, 0xe9, 0xc6, 0xe9, 0x24, 0x52},
+		},
+		{
+			cmd:  []byte{0x57, 0x0f, 0x4f, 0x81, 0x01},
+			want: []byte{0x57, 0x2a, 0xf0, 0xf1, 0x69, 0xe8, 0x46, 0xe9},
+		},
+	}
+
+	f := newTestEncryptedFramer(t)
+	for _, tt := range tests {
+		got, err := f.Frame(tt.cmd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("Frame(%x) expected %x, got %x", tt.cmd, tt.want, got)
+		}
+	}
+}
+
+func TestEncryptedFramerUnframe(t *testing.T) {
+	f := newTestEncryptedFramer(t)
+
+	got, err := f.Unframe([]byte{0x01, 0x00, 0x00, 0x00, 0x67, 0xc3, 0xc7, 0xe8, 0x34})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{0x01, 0x01, 0x64, 0x00, 0x00, 0x00}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Unframe expected %x, got %x", want, got)
+	}
+}
+
+func TestEncryptedFramerWithoutHandshake(t *testing.T) {
+	f, err := NewEncryptedFramer(testKeyID, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Frame([]byte{0x57, 0x0f, 0x4f, 0x81, 0x01}); err == nil {
+		t.Fatal("expected error, but got nil")
+	}
+}
+
+func TestNewEncryptedFramerWithInvalidKey(t *testing.T) {
+	tests := []struct {
+		keyID string
+		key   string
+	}{
+		{keyID: "2a2a", key: testKey},
+		{keyID: "zz", key: testKey},
+		{keyID: testKeyID, key: "0001"},
+	}
+
+	for _, tt := range tests {
+		if _, err := NewEncryptedFramer(tt.keyID, tt.key); err == nil {
+			t.Errorf("expected error for keyID=%q key=%q, but got nil", tt.keyID, tt.key)
+		}
+	}
+}
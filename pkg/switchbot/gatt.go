@@ -0,0 +1,130 @@
+package switchbot
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// maxAttributeLen is maximum length of GATT attribute value.
+const maxAttributeLen = 512
+
+// GATTService represents GATT service discovered by DiscoverGATT.
+type GATTService struct {
+	UUID            string                `json:"uuid"`
+	Characteristics []*GATTCharacteristic `json:"characteristics"`
+}
+
+// GATTCharacteristic represents GATT characteristic discovered by DiscoverGATT.
+type GATTCharacteristic struct {
+	UUID string `json:"uuid"`
+	// Properties are GATT properties such as "read" and "notify".
+	// Properties are nil if the platform does not report them.
+	Properties []string `json:"properties,omitempty"`
+	// Value is value read from the characteristic.
+	Value HexBytes `json:"value,omitempty"`
+	// ReadError is reason why Value could not be read.
+	ReadError string `json:"read_error,omitempty"`
+}
+
+// characteristicProperties is implemented by Characteristic which knows its GATT properties.
+type characteristicProperties interface {
+	Properties() ([]string, error)
+}
+
+// DiscoverGATT connects to SwitchBot filter by addr argument and discovers
+// every GATT service and characteristic of it.
+// Values of readable characteristics are read as well.
+// If SwitchBot is not found within timeout, DiscoverGATT returns TimeoutError.
+// Deadlines of connection and discovery are set by SetTimeouts.
+func DiscoverGATT(ctx context.Context, addr string, timeout time.Duration) ([]*GATTService, error) {
+	return defaultClient.DiscoverGATT(ctx, addr, WithScanTimeout(timeout))
+}
+
+func discoverGATTWithOptions(ctx context.Context, addr string, o *options) ([]*GATTService, error) {
+	res, err := scanAddr(ctx, addr, o)
+	if err != nil {
+		return nil, err
+	}
+
+	l := o.logger.With("addr", res.Addr)
+	dev, err := connectPeripheral(ctx, o, res.Addr)
+	if err != nil {
+		l.Warn("failed to connect", "error", err)
+		return nil, err
+	}
+	defer dev.Disconnect()
+
+	var ret []*GATTService
+	err = runPhase(ctx, res.Addr, PhaseDiscovery, o.timeouts.Discovery, func() error {
+		var err error
+		ret, err = discoverGATT(dev, l)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// discoverGATT discovers every GATT service and characteristic of dev.
+func discoverGATT(dev Peripheral, l *slog.Logger) ([]*GATTService, error) {
+	srvcs, err := dev.DiscoverServices(nil)
+	if err != nil {
+		l.Warn("failed to discover services", "error", err)
+		return nil, err
+	}
+	l.Debug("discovered services", "count", len(srvcs))
+
+	ret := make([]*GATTService, 0, len(srvcs))
+	for _, srvc := range srvcs {
+		chars, err := srvc.DiscoverCharacteristics(nil)
+		if err != nil {
+			l.Warn("failed to discover characteristics", "service", srvc.UUID().String(), "error", err)
+			return nil, err
+		}
+
+		gs := &GATTService{UUID: srvc.UUID().String()}
+		for _, char := range chars {
+			gs.Characteristics = append(gs.Characteristics, newGATTCharacteristic(char))
+		}
+		ret = append(ret, gs)
+	}
+	return ret, nil
+}
+
+func newGATTCharacteristic(char Characteristic) *GATTCharacteristic {
+	gc := &GATTCharacteristic{UUID: char.UUID().String()}
+	if cp, ok := char.(characteristicProperties); ok {
+		if props, err := cp.Properties(); err == nil {
+			gc.Properties = props
+		}
+	}
+
+	// Characteristics are read only if they are readable or readability is unknown.
+	if gc.Properties != nil && !containsString(gc.Properties, "read") {
+		return gc
+	}
+	buf := make([]byte, maxAttributeLen)
+	n, err := char.Read(buf)
+	if err != nil {
+		gc.ReadError = err.Error()
+		return gc
+	}
+	gc.Value = buf[:n]
+	return gc
+}
+
+// gattFlagsKey returns key of GATT properties of characteristic char of service srvc.
+func gattFlagsKey(srvc, char string) string {
+	return srvc + "/" + char
+}
+
+func containsString(arr []string, s string) bool {
+	for _, el := range arr {
+		if el == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package switchbot
+
+import (
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// gattFlags returns GATT properties of every characteristic of addr connected by adapter id,
+// keyed by gattFlagsKey. Empty id is the default adapter hci0.
+// Properties are reported by BlueZ, and characteristics are matched by path of their service.
+func gattFlags(id, addr string) (map[string][]string, error) {
+	if id == "" {
+		id = "hci0"
+	}
+	bus, err := dbus.SystemBus()
+	if err != nil {
+		return nil, err
+	}
+
+	var objs map[dbus.ObjectPath]map[string]map[string]dbus.Variant
+	err = bus.Object("org.bluez", "/").Call("org.freedesktop.DBus.ObjectManager.GetManagedObjects", 0).Store(&objs)
+	if err != nil {
+		return nil, err
+	}
+
+	dev := "/org/bluez/" + id + "/dev_" + strings.ReplaceAll(strings.ToUpper(addr), ":", "_") + "/"
+	srvcs := make(map[dbus.ObjectPath]string)
+	for path, ifaces := range objs {
+		srvc, ok := ifaces["org.bluez.GattService1"]
+		if !ok || !strings.HasPrefix(string(path), dev) {
+			continue
+		}
+		if u, ok := srvc["UUID"].Value().(string); ok {
+			srvcs[path] = u
+		}
+	}
+
+	ret := make(map[string][]string)
+	for _, ifaces := range objs {
+		char, ok := ifaces["org.bluez.GattCharacteristic1"]
+		if !ok {
+			continue
+		}
+		srvcPath, _ := char["Service"].Value().(dbus.ObjectPath)
+		srvcUUID, ok := srvcs[srvcPath]
+		if !ok {
+			continue
+		}
+		u, ok := char["UUID"].Value().(string)
+		if !ok {
+			continue
+		}
+		flags, _ := char["Flags"].Value().([]string)
+		ret[gattFlagsKey(srvcUUID, u)] = flags
+	}
+	return ret, nil
+}
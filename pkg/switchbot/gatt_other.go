@@ -0,0 +1,12 @@
+//go:build !linux
+
+package switchbot
+
+import (
+	"errors"
+)
+
+// gattFlags is not supported other than Linux.
+func gattFlags(id, addr string) (map[string][]string, error) {
+	return nil, errors.New("GATT properties are not available on this platform")
+}
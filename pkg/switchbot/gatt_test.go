@@ -0,0 +1,55 @@
+package switchbot
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDiscoverGATT(t *testing.T) {
+	useTestTransport(t, newTestReplayTransport(t, "testdata/gatt.jsonl"))
+
+	srvcs, err := DiscoverGATT(context.Background(), "11:22:33:44:55:66", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(srvcs) != 2 {
+		t.Fatalf("expected 2 services, got %d", len(srvcs))
+	}
+
+	gap := srvcs[0]
+	if gap.UUID != "00001800-0000-1000-8000-00805f9b34fb" || len(gap.Characteristics) != 1 {
+		t.Fatalf("unexpected service %+v", gap)
+	}
+	if name := string(gap.Characteristics[0].Value); name != "WoHand" {
+		t.Errorf("expected device name WoHand, got %q", name)
+	}
+
+	sb := srvcs[1]
+	if sb.UUID != serviceUUID.String() || len(sb.Characteristics) != 2 {
+		t.Fatalf("unexpected service %+v", sb)
+	}
+	for _, c := range sb.Characteristics {
+		if c.Value != nil || c.ReadError == "" {
+			t.Errorf("expected read error for unreadable characteristic %+v", c)
+		}
+	}
+}
+
+func TestAdapterPeripheralCharacteristicFlags(t *testing.T) {
+	p := &adapterPeripheral{addr: "11:22:33:44:55:66"}
+	p.flagsOnce.Do(func() {
+		p.flags = map[string][]string{
+			gattFlagsKey(serviceUUID.String(), commandUUID.String()):   {"write-without-response"},
+			gattFlagsKey(serviceUUID.String(), subscribeUUID.String()): {"notify"},
+		}
+	})
+
+	flags, err := p.characteristicFlags(serviceUUID, subscribeUUID)
+	if err != nil || len(flags) != 1 || flags[0] != "notify" {
+		t.Errorf("unexpected flags %v, %v", flags, err)
+	}
+	if _, err := p.characteristicFlags(commandUUID, subscribeUUID); err == nil {
+		t.Error("expected error for characteristic of another service")
+	}
+}
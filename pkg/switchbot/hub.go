@@ -0,0 +1,39 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+func init() {
+	mustRegisterModel(&Model{
+		Name:       "HubMini",
+		ModelBytes: []byte{'m'},
+	})
+	mustRegisterModel(&Model{
+		Name:       "Hub2",
+		ModelBytes: []byte{'v'},
+		Decoder: func(mfrData, _ []byte) (interface{}, error) {
+			return NewHubInfoWithAdvertisement(mfrData)
+		},
+	})
+}
+
+// GetHubInfo retrieves Hub 2's sensor readings from its advertisement.
+// If advertisement is not received within timeout, GetHubInfo returns error.
+func GetHubInfo(ctx context.Context, addr string, timeout time.Duration) (*HubInfo, error) {
+	return defaultClient.GetHubInfo(ctx, addr, WithScanTimeout(timeout))
+}
+
+// GetHubInfo retrieves Hub 2's sensor readings from its advertisement with opts.
+func (c *Client) GetHubInfo(ctx context.Context, addr string, opts ...Option) (*HubInfo, error) {
+	res, err := c.scanAddr(ctx, addr, opts, "Hub2")
+	if err != nil {
+		return nil, err
+	}
+	if res.ManufacturerData == nil {
+		return nil, errors.New("manufacturer data is not found in advertisement")
+	}
+	return NewHubInfoWithAdvertisement(res.ManufacturerData)
+}
@@ -0,0 +1,45 @@
+package switchbot
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// HubInfo represents sensor readings of SwitchBot Hub 2.
+type HubInfo struct {
+	Temperature float64 `json:"temperature"`
+	Humidity    int     `json:"humidity"`
+	LightLevel  int     `json:"light_level"`
+	Fahrenheit  bool    `json:"fahrenheit"`
+}
+
+// NewHubInfoWithAdvertisement initialize HubInfo with manufacturer data
+// of Hub 2's advertisement.
+// Temperature is always celsius, Fahrenheit represents display unit of the Hub.
+func NewHubInfoWithAdvertisement(data []byte) (*HubInfo, error) {
+	if len(data) < 16 {
+		return nil, fmt.Errorf("hub advertisement is too short: %d bytes", len(data))
+	}
+
+	temp := float64(data[14]&0x7f) + float64(data[13]&0x0f)/10
+	if (data[14] & 0x80) == 0 {
+		temp = -temp
+	}
+
+	return &HubInfo{
+		Temperature: temp,
+		Humidity:    int(data[15] & 0x7f),
+		LightLevel:  int(data[12] & 0x1f),
+		Fahrenheit:  (data[15] & 0x80) != 0,
+	}, nil
+}
+
+// String returns formatted information
+func (i *HubInfo) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Temperature: %0.1f", i.Temperature))
+	buf.WriteString(fmt.Sprintf(", Humidity: %d", i.Humidity))
+	buf.WriteString(fmt.Sprintf(", LightLevel: %d", i.LightLevel))
+	buf.WriteString(fmt.Sprintf(", Fahrenheit: %t", i.Fahrenheit))
+	return buf.String()
+}
@@ -0,0 +1,39 @@
+package switchbot
+
+import "testing"
+
+func TestNewHubInfoWithAdvertisement(t *testing.T) {
+	tests := []struct {
+		got  []byte
+		want HubInfo
+	}{
+		{
+			got: []byte{0x60, 0x55, 0xf9, 0x11, 0x22, 0x33, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0a, 0x05, 0x97, 0x2d},
+			want: HubInfo{
+				Temperature: 23.5,
+				Humidity:    45,
+				LightLevel:  10,
+				Fahrenheit:  false,
+			},
+		},
+		{
+			got: []byte{0x60, 0x55, 0xf9, 0x11, 0x22, 0x33, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x02, 0x03, 0xbc},
+			want: HubInfo{
+				Temperature: -3.2,
+				Humidity:    60,
+				LightLevel:  1,
+				Fahrenheit:  true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := NewHubInfoWithAdvertisement(tt.got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *got != tt.want {
+			t.Errorf("NewHubInfoWithAdvertisement(%x) expected %+v, got %+v", tt.got, tt.want, *got)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Humidifier represents SwitchBot Humidifier device.
+type Humidifier struct {
+	Addr string
+
+	conn
+}
+
+// Commands of Humidifier, which are registered as Model.Commands as well.
+var (
+	humidifierOn  = []byte{0x57, 0x0f, 0x43, 0x81, 0x01, 0x01, 0x80, 0xff, 0xff, 0xff, 0xff}
+	humidifierOff = []byte{0x57, 0x0f, 0x43, 0x81, 0x01, 0x00, 0x80, 0xff, 0xff, 0xff, 0xff}
+)
+
+func init() {
+	mustRegisterModel(&Model{
+		Name:       "Humidifier",
+		ModelBytes: []byte{'e'},
+		Decoder: func(_, svcData []byte) (interface{}, error) {
+			return NewHumidifierInfoWithAdvertisement(svcData)
+		},
+		Commands: map[string][]byte{
+			"on":  humidifierOn,
+			"off": humidifierOff,
+		},
+	})
+}
+
+// NewHumidifier initializes humidifier object.
+func NewHumidifier(addr string) *Humidifier {
+	return &Humidifier{Addr: normalizeAddr(addr), conn: newConn(addr)}
+}
+
+// ConnectHumidifier connects to SwitchBot Humidifier filter by addr argument.
+// If connection failed within timeout, ConnectHumidifier returns error.
+func ConnectHumidifier(ctx context.Context, addr string, timeout time.Duration) (*Humidifier, error) {
+	return defaultClient.ConnectHumidifier(ctx, addr, WithScanTimeout(timeout))
+}
+
+// ConnectHumidifier connects to SwitchBot Humidifier filter by addr argument with opts.
+func (c *Client) ConnectHumidifier(ctx context.Context, addr string, opts ...Option) (*Humidifier, error) {
+	res, cn, err := c.connect(ctx, addr, opts, "Humidifier")
+	if err != nil {
+		return nil, err
+	}
+	return &Humidifier{Addr: res.Addr, conn: cn}, nil
+}
+
+// GetHumidifierInfo retrieves Humidifier's current state from its advertisement.
+// If advertisement is not received within timeout, GetHumidifierInfo returns error.
+func GetHumidifierInfo(ctx context.Context, addr string, timeout time.Duration) (*HumidifierInfo, error) {
+	return defaultClient.GetHumidifierInfo(ctx, addr, WithScanTimeout(timeout))
+}
+
+// GetHumidifierInfo retrieves Humidifier's current state from its advertisement with opts.
+func (c *Client) GetHumidifierInfo(ctx context.Context, addr string, opts ...Option) (*HumidifierInfo, error) {
+	res, err := c.scanAddr(ctx, addr, opts, "Humidifier")
+	if err != nil {
+		return nil, err
+	}
+	if res.ServiceData == nil {
+		return nil, errors.New("service data is not found in advertisement")
+	}
+	return NewHumidifierInfoWithAdvertisement(res.ServiceData)
+}
+
+// On turns on the Humidifier in auto mode.
+func (h *Humidifier) On(wait bool) error {
+	_, err := h.act("on", humidifierOn, wait)
+	return err
+}
+
+// Off turns off the Humidifier.
+func (h *Humidifier) Off(wait bool) error {
+	_, err := h.act("off", humidifierOff, wait)
+	return err
+}
+
+// SetAuto sets the Humidifier to auto mode.
+func (h *Humidifier) SetAuto(wait bool) error {
+	return h.On(wait)
+}
+
+// SetLevel sets target humidity level of the Humidifier.
+// level must be between 1 and 100.
+func (h *Humidifier) SetLevel(level int, wait bool) error {
+	if level < 1 || level > 100 {
+		return fmt.Errorf("level must be between 1 and 100, got %d", level)
+	}
+	_, err := h.act(fmt.Sprintf("level %d", level), []byte{0x57, 0x0f, 0x43, 0x81, 0x01, 0x01, byte(level), 0xff, 0xff, 0xff, 0xff}, wait)
+	return err
+}
@@ -0,0 +1,39 @@
+package switchbot
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// HumidifierInfo represents current SwitchBot Humidifier's information.
+type HumidifierInfo struct {
+	On         bool `json:"on"`
+	Auto       bool `json:"auto"`
+	Level      int  `json:"level"`
+	WaterEmpty bool `json:"water_empty"`
+}
+
+// NewHumidifierInfoWithAdvertisement initialize HumidifierInfo with service data
+// of Humidifier's advertisement.
+func NewHumidifierInfoWithAdvertisement(data []byte) (*HumidifierInfo, error) {
+	if len(data) < 5 {
+		return nil, fmt.Errorf("humidifier advertisement is too short: %d bytes", len(data))
+	}
+
+	return &HumidifierInfo{
+		On:         (data[1] & 0x80) != 0,
+		Auto:       (data[4] & 0x80) != 0,
+		Level:      int(data[4] & 0x7f),
+		WaterEmpty: (data[3] & 0x20) != 0,
+	}, nil
+}
+
+// String returns formatted information
+func (i *HumidifierInfo) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("On: %t", i.On))
+	buf.WriteString(fmt.Sprintf(", Auto: %t", i.Auto))
+	buf.WriteString(fmt.Sprintf(", Level: %d", i.Level))
+	buf.WriteString(fmt.Sprintf(", WaterEmpty: %t", i.WaterEmpty))
+	return buf.String()
+}
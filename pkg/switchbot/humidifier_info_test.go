@@ -0,0 +1,24 @@
+package switchbot
+
+import "testing"
+
+func TestNewHumidifierInfoWithAdvertisement(t *testing.T) {
+	r := []byte{0x65, 0x80, 0x00, 0x20, 0x3c, 0x00, 0x00, 0x00}
+
+	got, err := NewHumidifierInfoWithAdvertisement(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.On != true {
+		t.Errorf("On is not correct, got %v", got.On)
+	}
+	if got.Auto != false {
+		t.Errorf("Auto is not correct, got %v", got.Auto)
+	}
+	if got.Level != 60 {
+		t.Errorf("Level is not correct, got %v", got.Level)
+	}
+	if got.WaterEmpty != true {
+		t.Errorf("WaterEmpty is not correct, got %v", got.WaterEmpty)
+	}
+}
@@ -46,3 +46,44 @@ func (i *BotInfo) String() string {
 	buf.WriteString(fmt.Sprintf(", HoldSec: %d", i.HoldSec))
 	return buf.String()
 }
+
+// BotState represents SwitchBot's state broadcasted by advertisement.
+type BotState struct {
+	Battery   int  `json:"battery"`
+	StateMode bool `json:"state_mode"`
+	On        bool `json:"on"`
+}
+
+// NewBotStateWithAdvertisement initialize BotState with service data
+// of SwitchBot's advertisement.
+func NewBotStateWithAdvertisement(data []byte) (*BotState, error) {
+	if len(data) < 3 {
+		return nil, fmt.Errorf("bot advertisement is too short: %d bytes", len(data))
+	}
+
+	st := (data[1] & 0x80) != 0
+	return &BotState{
+		Battery:   int(data[2] & 0x7f),
+		StateMode: st,
+		On:        st && (data[1]&0x40) == 0,
+	}, nil
+}
+
+// String returns formatted state
+func (s *BotState) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Battery: %d", s.Battery))
+	buf.WriteString(fmt.Sprintf(", StateMode: %t", s.StateMode))
+	buf.WriteString(fmt.Sprintf(", On: %t", s.On))
+	return buf.String()
+}
+
+// BatteryLevel returns battery level in percent.
+func (i *BotInfo) BatteryLevel() int {
+	return i.Battery
+}
+
+// BatteryLevel returns battery level in percent.
+func (s *BotState) BatteryLevel() int {
+	return s.Battery
+}
@@ -28,3 +28,21 @@ func TestNewInfoWithRawInfo(t *testing.T) {
 		t.Errorf("HoldSec is not correct, got %v", got.HoldSec)
 	}
 }
+
+func TestNewBotStateWithAdvertisement(t *testing.T) {
+	r := []byte{'H', 0x80, 0xe4}
+
+	got, err := NewBotStateWithAdvertisement(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Battery != 100 {
+		t.Errorf("Battery is not correct, got %v", got.Battery)
+	}
+	if got.StateMode != true {
+		t.Errorf("StateMode is not correct, got %v", got.StateMode)
+	}
+	if got.On != true {
+		t.Errorf("On is not correct, got %v", got.On)
+	}
+}
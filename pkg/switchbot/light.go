@@ -0,0 +1,172 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// light represents commands shared by SwitchBot Color Bulb and Strip Light.
+type light struct {
+	conn
+
+	header byte
+}
+
+// On turns on the light.
+func (l *light) On(wait bool) error {
+	_, err := l.act("on", []byte{0x57, 0x0f, l.header, 0x01, 0x01}, wait)
+	return err
+}
+
+// Off turns off the light.
+func (l *light) Off(wait bool) error {
+	_, err := l.act("off", []byte{0x57, 0x0f, l.header, 0x01, 0x02}, wait)
+	return err
+}
+
+// SetBrightness sets brightness of the light.
+// level must be between 1 and 100.
+func (l *light) SetBrightness(level int, wait bool) error {
+	if err := validBrightness(level); err != nil {
+		return err
+	}
+	_, err := l.act(fmt.Sprintf("brightness %d", level), []byte{0x57, 0x0f, l.header, 0x01, 0x14, byte(level)}, wait)
+	return err
+}
+
+// SetRGB sets color and brightness of the light.
+// brightness must be between 1 and 100.
+func (l *light) SetRGB(brightness int, r, g, b uint8, wait bool) error {
+	if err := validBrightness(brightness); err != nil {
+		return err
+	}
+	_, err := l.act(fmt.Sprintf("color %02x%02x%02x", r, g, b), []byte{0x57, 0x0f, l.header, 0x01, 0x16, byte(brightness), r, g, b}, wait)
+	return err
+}
+
+func validBrightness(level int) error {
+	if level < 1 || level > 100 {
+		return fmt.Errorf("brightness must be between 1 and 100, got %d", level)
+	}
+	return nil
+}
+
+// SetEffect starts built-in effect(scene) of the light specified by effect number.
+func (l *light) SetEffect(effect int, wait bool) error {
+	if effect < 0 || effect > 0xff {
+		return fmt.Errorf("effect must be between 0 and 255, got %d", effect)
+	}
+	_, err := l.act(fmt.Sprintf("effect %d", effect), []byte{0x57, 0x0f, l.header, 0x01, 0x18, byte(effect)}, wait)
+	return err
+}
+
+// Bulb represents SwitchBot Color Bulb device.
+type Bulb struct {
+	Addr string
+
+	light
+}
+
+func init() {
+	decoder := func(mfrData, _ []byte) (interface{}, error) {
+		return NewLightInfoWithAdvertisement(mfrData)
+	}
+	mustRegisterModel(&Model{
+		Name:       "Bulb",
+		ModelBytes: []byte{'u'},
+		Decoder:    decoder,
+		Commands: map[string][]byte{
+			"on":  {0x57, 0x0f, 0x47, 0x01, 0x01},
+			"off": {0x57, 0x0f, 0x47, 0x01, 0x02},
+		},
+	})
+	mustRegisterModel(&Model{
+		Name:       "StripLight",
+		ModelBytes: []byte{'r'},
+		Decoder:    decoder,
+		Commands: map[string][]byte{
+			"on":  {0x57, 0x0f, 0x49, 0x01, 0x01},
+			"off": {0x57, 0x0f, 0x49, 0x01, 0x02},
+		},
+	})
+}
+
+// NewBulb initializes bulb object.
+func NewBulb(addr string) *Bulb {
+	return &Bulb{Addr: normalizeAddr(addr), light: light{conn: newConn(addr), header: 0x47}}
+}
+
+// ConnectBulb connects to SwitchBot Color Bulb filter by addr argument.
+// If connection failed within timeout, ConnectBulb returns error.
+func ConnectBulb(ctx context.Context, addr string, timeout time.Duration) (*Bulb, error) {
+	return defaultClient.ConnectBulb(ctx, addr, WithScanTimeout(timeout))
+}
+
+// ConnectBulb connects to SwitchBot Color Bulb filter by addr argument with opts.
+func (c *Client) ConnectBulb(ctx context.Context, addr string, opts ...Option) (*Bulb, error) {
+	res, cn, err := c.connect(ctx, addr, opts, "Bulb")
+	if err != nil {
+		return nil, err
+	}
+	return &Bulb{Addr: res.Addr, light: light{conn: cn, header: 0x47}}, nil
+}
+
+// SetColorTemp sets color temperature and brightness of the bulb.
+// brightness must be between 1 and 100, and kelvin must be between 2700 and 6500.
+func (b *Bulb) SetColorTemp(brightness, kelvin int, wait bool) error {
+	if err := validBrightness(brightness); err != nil {
+		return err
+	}
+	if kelvin < 2700 || kelvin > 6500 {
+		return fmt.Errorf("color temperature must be between 2700 and 6500, got %d", kelvin)
+	}
+	_, err := b.act(fmt.Sprintf("temp %d", kelvin), []byte{0x57, 0x0f, b.header, 0x01, 0x17, byte(brightness), byte(kelvin >> 8), byte(kelvin)}, wait)
+	return err
+}
+
+// StripLight represents SwitchBot Strip Light device.
+type StripLight struct {
+	Addr string
+
+	light
+}
+
+// NewStripLight initializes strip light object.
+func NewStripLight(addr string) *StripLight {
+	return &StripLight{Addr: normalizeAddr(addr), light: light{conn: newConn(addr), header: 0x49}}
+}
+
+// ConnectStripLight connects to SwitchBot Strip Light filter by addr argument.
+// If connection failed within timeout, ConnectStripLight returns error.
+func ConnectStripLight(ctx context.Context, addr string, timeout time.Duration) (*StripLight, error) {
+	return defaultClient.ConnectStripLight(ctx, addr, WithScanTimeout(timeout))
+}
+
+// ConnectStripLight connects to SwitchBot Strip Light filter by addr argument with opts.
+func (c *Client) ConnectStripLight(ctx context.Context, addr string, opts ...Option) (*StripLight, error) {
+	res, cn, err := c.connect(ctx, addr, opts, "StripLight")
+	if err != nil {
+		return nil, err
+	}
+	return &StripLight{Addr: res.Addr, light: light{conn: cn, header: 0x49}}, nil
+}
+
+// GetLightInfo retrieves Color Bulb's or Strip Light's current state from its advertisement.
+// If advertisement is not received within timeout, GetLightInfo returns error.
+func GetLightInfo(ctx context.Context, addr string, timeout time.Duration) (*LightInfo, error) {
+	return defaultClient.GetLightInfo(ctx, addr, WithScanTimeout(timeout))
+}
+
+// GetLightInfo retrieves Color Bulb's or Strip Light's current state from its advertisement with opts.
+func (c *Client) GetLightInfo(ctx context.Context, addr string, opts ...Option) (*LightInfo, error) {
+	res, err := c.scanAddr(ctx, addr, opts, "Bulb", "StripLight")
+	if err != nil {
+		return nil, err
+	}
+	if res.ManufacturerData == nil {
+		return nil, errors.New("manufacturer data is not found in advertisement")
+	}
+	return NewLightInfoWithAdvertisement(res.ManufacturerData)
+}
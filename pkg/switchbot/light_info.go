@@ -0,0 +1,84 @@
+package switchbot
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// LightColorMode represents color mode of Color Bulb and Strip Light.
+type LightColorMode int
+
+// LightColorMode values reported by Color Bulb and Strip Light.
+const (
+	LightColorModeUnknown LightColorMode = iota
+	LightColorModeColorTemp
+	LightColorModeRGB
+	LightColorModeEffect
+	LightColorModeMusic
+	LightColorModeController
+)
+
+// String returns name of the color mode.
+func (m LightColorMode) String() string {
+	switch m {
+	case LightColorModeColorTemp:
+		return "color_temp"
+	case LightColorModeRGB:
+		return "rgb"
+	case LightColorModeEffect:
+		return "effect"
+	case LightColorModeMusic:
+		return "music"
+	case LightColorModeController:
+		return "controller"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (m LightColorMode) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// LightInfo represents current Color Bulb's or Strip Light's information.
+type LightInfo struct {
+	On         bool           `json:"on"`
+	Brightness int            `json:"brightness"`
+	ColorMode  LightColorMode `json:"color_mode"`
+	Delay      bool           `json:"delay"`
+	Preset     bool           `json:"preset"`
+	Speed      int            `json:"speed"`
+	LoopIndex  int            `json:"loop_index"`
+}
+
+// NewLightInfoWithAdvertisement initialize LightInfo with manufacturer data
+// of Color Bulb's or Strip Light's advertisement.
+func NewLightInfoWithAdvertisement(data []byte) (*LightInfo, error) {
+	if len(data) < 11 {
+		return nil, fmt.Errorf("light advertisement is too short: %d bytes", len(data))
+	}
+
+	return &LightInfo{
+		On:         (data[7] & 0x80) != 0,
+		Brightness: int(data[7] & 0x7f),
+		ColorMode:  LightColorMode(data[8] & 0x07),
+		Delay:      (data[8] & 0x40) != 0,
+		Preset:     (data[8] & 0x20) != 0,
+		Speed:      int(data[9] & 0x7f),
+		LoopIndex:  int(data[10] & 0xfe),
+	}, nil
+}
+
+// String returns formatted information
+func (i *LightInfo) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("On: %t", i.On))
+	buf.WriteString(fmt.Sprintf(", Brightness: %d", i.Brightness))
+	buf.WriteString(fmt.Sprintf(", ColorMode: %s", i.ColorMode))
+	buf.WriteString(fmt.Sprintf(", Delay: %t", i.Delay))
+	buf.WriteString(fmt.Sprintf(", Preset: %t", i.Preset))
+	buf.WriteString(fmt.Sprintf(", Speed: %d", i.Speed))
+	buf.WriteString(fmt.Sprintf(", LoopIndex: %d", i.LoopIndex))
+	return buf.String()
+}
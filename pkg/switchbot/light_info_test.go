@@ -0,0 +1,56 @@
+package switchbot
+
+import "testing"
+
+func TestNewLightInfoWithAdvertisement(t *testing.T) {
+	r := []byte{0x60, 0x55, 0xf9, 0x11, 0x22, 0x33, 0x1a, 0xcb, 0x22, 0x32, 0x05}
+
+	got, err := NewLightInfoWithAdvertisement(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.On != true {
+		t.Errorf("On is not correct, got %v", got.On)
+	}
+	if got.Brightness != 75 {
+		t.Errorf("Brightness is not correct, got %v", got.Brightness)
+	}
+	if got.ColorMode != LightColorModeRGB {
+		t.Errorf("ColorMode is not correct, got %v", got.ColorMode)
+	}
+	if got.Delay != false {
+		t.Errorf("Delay is not correct, got %v", got.Delay)
+	}
+	if got.Preset != true {
+		t.Errorf("Preset is not correct, got %v", got.Preset)
+	}
+	if got.Speed != 50 {
+		t.Errorf("Speed is not correct, got %v", got.Speed)
+	}
+	if got.LoopIndex != 4 {
+		t.Errorf("LoopIndex is not correct, got %v", got.LoopIndex)
+	}
+}
+
+func TestNewLightInfoWithDelay(t *testing.T) {
+	r := []byte{0x60, 0x55, 0xf9, 0x11, 0x22, 0x33, 0x1a, 0x64, 0x43, 0x00, 0x00}
+
+	got, err := NewLightInfoWithAdvertisement(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.On || got.Brightness != 100 || got.ColorMode != LightColorModeEffect {
+		t.Errorf("unexpected info %+v", got)
+	}
+	if got.Delay != true || got.Preset != false {
+		t.Errorf("Delay and Preset are not correct, got %v and %v", got.Delay, got.Preset)
+	}
+}
+
+func TestNewLightInfoWithShortAdvertisement(t *testing.T) {
+	r := []byte{0x60, 0x55, 0xf9, 0x11, 0x22, 0x33, 0x1a, 0xcb}
+
+	if _, err := NewLightInfoWithAdvertisement(r); err == nil {
+		t.Fatal("expected error, but got nil")
+	}
+}
@@ -0,0 +1,63 @@
+package switchbot
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"tinygo.org/x/bluetooth"
+)
+
+func newTestBulb(t *testing.T) (*Bulb, *fakeTransport) {
+	ft := newFakeTransport(
+		&Advertisement{Addr: "33:33:33:33:33:33", ServiceData: []byte{'u'}},
+		map[bluetooth.UUID][]bluetooth.UUID{serviceUUID: {commandUUID, subscribeUUID}},
+	)
+	c := NewClient(WithTransport(ft), WithScanTimeout(time.Second))
+	t.Cleanup(func() { c.Close() })
+
+	bulb, err := c.ConnectBulb(context.Background(), "33:33:33:33:33:33")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bulb, ft
+}
+
+func TestLightCommands(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func(b *Bulb) error
+		want []byte
+	}{
+		{"rgb", func(b *Bulb) error { return b.SetRGB(50, 0xff, 0x80, 0x00, true) }, []byte{0x57, 0x0f, 0x47, 0x01, 0x16, 0x32, 0xff, 0x80, 0x00}},
+		{"color temp", func(b *Bulb) error { return b.SetColorTemp(100, 2700, true) }, []byte{0x57, 0x0f, 0x47, 0x01, 0x17, 0x64, 0x0a, 0x8c}},
+		{"brightness", func(b *Bulb) error { return b.SetBrightness(30, true) }, []byte{0x57, 0x0f, 0x47, 0x01, 0x14, 0x1e}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bulb, ft := newTestBulb(t)
+			if err := tt.f(bulb); err != nil {
+				t.Fatal(err)
+			}
+			writes := ft.written()
+			if len(writes) != 1 || !bytes.Equal(writes[0], tt.want) {
+				t.Errorf("written %x, want %x", writes, tt.want)
+			}
+		})
+	}
+}
+
+func TestLightInvalidBrightness(t *testing.T) {
+	bulb, ft := newTestBulb(t)
+	if err := bulb.SetRGB(0, 0xff, 0xff, 0xff, true); err == nil {
+		t.Error("expected brightness 0 to be rejected")
+	}
+	if err := bulb.SetColorTemp(101, 4000, true); err == nil {
+		t.Error("expected brightness 101 to be rejected")
+	}
+	if n := len(ft.written()); n != 0 {
+		t.Errorf("expected nothing to be written, got %d commands", n)
+	}
+}
@@ -0,0 +1,133 @@
+package switchbot
+
+import (
+	"context"
+	"time"
+)
+
+// Lock represents SwitchBot Lock and Lock Pro device.
+// Lock requires encrypted commands, so SetEncryptionKey must be called
+// before calling Lock/Unlock/Status function.
+type Lock struct {
+	Addr string
+	Pro  bool
+
+	conn
+}
+
+func init() {
+	mustRegisterModel(&Model{
+		Name:       "Lock",
+		ModelBytes: []byte{'o'},
+		Commands: map[string][]byte{
+			"lock":   {0x57, 0x0f, 0x4e, 0x01, 0x01, 0x10, 0x00},
+			"unlock": {0x57, 0x0f, 0x4e, 0x01, 0x01, 0x10, 0x80},
+			"status": {0x57, 0x0f, 0x4f, 0x81, 0x01},
+		},
+		ResponseDecoders: map[string]ResponseDecoder{
+			"570f4f81": func(res []byte) (interface{}, error) {
+				return NewLockInfoWithRawInfo(res)
+			},
+		},
+	})
+	mustRegisterModel(&Model{
+		Name:       "LockPro",
+		ModelBytes: []byte{'$'},
+		Commands: map[string][]byte{
+			"lock":   {0x57, 0x0f, 0x4e, 0x01, 0x01, 0x00, 0x00, 0x00},
+			"unlock": {0x57, 0x0f, 0x4e, 0x01, 0x01, 0x00, 0x00, 0x80},
+			"status": {0x57, 0x0f, 0x4f, 0x81, 0x02},
+		},
+		ResponseDecoders: map[string]ResponseDecoder{
+			"570f4f81": func(res []byte) (interface{}, error) {
+				return NewLockInfoWithRawInfo(res)
+			},
+		},
+	})
+}
+
+// NewLock initializes lock object.
+func NewLock(addr string) *Lock {
+	return &Lock{Addr: normalizeAddr(addr), conn: newConn(addr)}
+}
+
+// ConnectLock connects to SwitchBot Lock filter by addr argument.
+// If connection failed within timeout, ConnectLock returns error.
+func ConnectLock(ctx context.Context, addr string, timeout time.Duration) (*Lock, error) {
+	return defaultClient.ConnectLock(ctx, addr, WithScanTimeout(timeout))
+}
+
+// ConnectLock connects to SwitchBot Lock filter by addr argument with opts.
+func (c *Client) ConnectLock(ctx context.Context, addr string, opts ...Option) (*Lock, error) {
+	res, cn, err := c.connect(ctx, addr, opts, "Lock", "LockPro")
+	if err != nil {
+		return nil, err
+	}
+	return &Lock{Addr: res.Addr, conn: cn}, nil
+}
+
+// ConnectLockPro connects to SwitchBot Lock Pro filter by addr argument.
+// If connection failed within timeout, ConnectLockPro returns error.
+func ConnectLockPro(ctx context.Context, addr string, timeout time.Duration) (*Lock, error) {
+	return defaultClient.ConnectLockPro(ctx, addr, WithScanTimeout(timeout))
+}
+
+// ConnectLockPro connects to SwitchBot Lock Pro filter by addr argument with opts.
+func (c *Client) ConnectLockPro(ctx context.Context, addr string, opts ...Option) (*Lock, error) {
+	l, err := c.ConnectLock(ctx, addr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	l.Pro = true
+	return l, nil
+}
+
+// SetEncryptionKey sets key ID and encryption key of the Lock.
+// Both keyID and key are hex encoded string which can be retrieved from SwitchBot account.
+func (l *Lock) SetEncryptionKey(keyID, key string) error {
+	f, err := NewEncryptedFramer(keyID, key)
+	if err != nil {
+		return err
+	}
+	l.SetFramer(f)
+	return nil
+}
+
+// Lock locks the Lock.
+func (l *Lock) Lock(wait bool) error {
+	var cmd []byte
+	if l.Pro {
+		cmd = []byte{0x57, 0x0f, 0x4e, 0x01, 0x01, 0x00, 0x00, 0x00}
+	} else {
+		cmd = []byte{0x57, 0x0f, 0x4e, 0x01, 0x01, 0x10, 0x00}
+	}
+	_, err := l.act("lock", cmd, wait)
+	return err
+}
+
+// Unlock unlocks the Lock.
+func (l *Lock) Unlock(wait bool) error {
+	var cmd []byte
+	if l.Pro {
+		cmd = []byte{0x57, 0x0f, 0x4e, 0x01, 0x01, 0x00, 0x00, 0x80}
+	} else {
+		cmd = []byte{0x57, 0x0f, 0x4e, 0x01, 0x01, 0x10, 0x80}
+	}
+	_, err := l.act("unlock", cmd, wait)
+	return err
+}
+
+// Status retrieves Lock's current status.
+func (l *Lock) Status() (*LockInfo, error) {
+	var cmd []byte
+	if l.Pro {
+		cmd = []byte{0x57, 0x0f, 0x4f, 0x81, 0x02}
+	} else {
+		cmd = []byte{0x57, 0x0f, 0x4f, 0x81, 0x01}
+	}
+	res, err := l.trigger(cmd, true)
+	if err != nil {
+		return nil, err
+	}
+	return NewLockInfoWithRawInfo(res)
+}
@@ -0,0 +1,83 @@
+package switchbot
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// LockState represents state of the Lock.
+type LockState int
+
+// LockState values reported by the Lock.
+const (
+	LockStateLocked LockState = iota
+	LockStateUnlocked
+	LockStateLocking
+	LockStateUnlocking
+	LockStateLockingStop
+	LockStateUnlockingStop
+	LockStateNotFullyLocked
+)
+
+// String returns name of the state.
+func (s LockState) String() string {
+	switch s {
+	case LockStateLocked:
+		return "locked"
+	case LockStateUnlocked:
+		return "unlocked"
+	case LockStateLocking:
+		return "locking"
+	case LockStateUnlocking:
+		return "unlocking"
+	case LockStateLockingStop:
+		return "locking_stop"
+	case LockStateUnlockingStop:
+		return "unlocking_stop"
+	case LockStateNotFullyLocked:
+		return "not_fully_locked"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s LockState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// LockInfo represents current SwitchBot Lock's information.
+type LockInfo struct {
+	State         LockState `json:"state"`
+	Calibrated    bool      `json:"calibrated"`
+	DoorOpen      bool      `json:"door_open"`
+	UnclosedAlarm bool      `json:"unclosed_alarm"`
+	UnlockedAlarm bool      `json:"unlocked_alarm"`
+}
+
+// NewLockInfoWithRawInfo initialize LockInfo with raw byte data.
+// This works with Lock.Status.
+func NewLockInfoWithRawInfo(info []byte) (*LockInfo, error) {
+	if len(info) < 3 {
+		return nil, fmt.Errorf("lock status is too short: %d bytes", len(info))
+	}
+
+	return &LockInfo{
+		State:         LockState((info[1] & 0x70) >> 4),
+		Calibrated:    (info[1] & 0x80) != 0,
+		DoorOpen:      (info[1] & 0x04) != 0,
+		UnclosedAlarm: (info[2] & 0x20) != 0,
+		UnlockedAlarm: (info[2] & 0x10) != 0,
+	}, nil
+}
+
+// String returns formatted information
+func (i *LockInfo) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("State: %s", i.State))
+	buf.WriteString(fmt.Sprintf(", Calibrated: %t", i.Calibrated))
+	buf.WriteString(fmt.Sprintf(", DoorOpen: %t", i.DoorOpen))
+	buf.WriteString(fmt.Sprintf(", UnclosedAlarm: %t", i.UnclosedAlarm))
+	buf.WriteString(fmt.Sprintf(", UnlockedAlarm: %t", i.UnlockedAlarm))
+	return buf.String()
+}
@@ -0,0 +1,27 @@
+package switchbot
+
+import "testing"
+
+func TestNewLockInfoWithRawInfo(t *testing.T) {
+	r := []byte{1, 0x94, 0x20}
+
+	got, err := NewLockInfoWithRawInfo(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.State != LockStateUnlocked {
+		t.Errorf("State is not correct, got %v", got.State)
+	}
+	if got.Calibrated != true {
+		t.Errorf("Calibrated is not correct, got %v", got.Calibrated)
+	}
+	if got.DoorOpen != true {
+		t.Errorf("DoorOpen is not correct, got %v", got.DoorOpen)
+	}
+	if got.UnclosedAlarm != true {
+		t.Errorf("UnclosedAlarm is not correct, got %v", got.UnclosedAlarm)
+	}
+	if got.UnlockedAlarm != false {
+		t.Errorf("UnlockedAlarm is not correct, got %v", got.UnlockedAlarm)
+	}
+}
@@ -0,0 +1,24 @@
+package switchbot
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SetLogger sets logger used by Scan, Connect and devices connected after the call.
+// If l is nil, logging is disabled. Logging is disabled by default.
+func SetLogger(l *slog.Logger) {
+	defaultClient.configure(WithLogger(l))
+}
+
+func newDiscardLogger() *slog.Logger {
+	return slog.New(discardHandler{})
+}
+
+// discardHandler is slog.Handler which discards all records.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
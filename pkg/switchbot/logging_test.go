@@ -0,0 +1,46 @@
+package switchbot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestLogger(t *testing.T) {
+	useTestTransport(t, newTestReplayTransport(t, "testdata/getinfo.jsonl"))
+
+	var buf bytes.Buffer
+	SetLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() {
+		SetLogger(nil)
+	})
+
+	bot, err := Connect(context.Background(), "11:22:33:44:55:66", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bot.GetInfo(); err != nil {
+		t.Fatal(err)
+	}
+
+	var found bool
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var rec map[string]interface{}
+		if err := dec.Decode(&rec); err != nil {
+			t.Fatal(err)
+		}
+		if rec["msg"] == "received notification" {
+			found = true
+			if rec["addr"] != "11:22:33:44:55:66" || rec["opcode"] != "02" || rec["response"] != "014f2d64000000980300034800" {
+				t.Errorf("unexpected log record %v", rec)
+			}
+		}
+	}
+	if !found {
+		t.Error("notification is not logged")
+	}
+}
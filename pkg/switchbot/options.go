@@ -0,0 +1,227 @@
+package switchbot
+
+import (
+	"log/slog"
+	"time"
+
+	"tinygo.org/x/bluetooth"
+)
+
+// Option configures ConnectWithOptions and ScanWithOptions.
+type Option func(o *options)
+
+// options is settings of a scan or connection.
+// Settings which are not specified by Option follow package settings,
+// such as SetTransport, SetLogger and SetTimeouts.
+type options struct {
+	transport Transport
+	// adapters are candidates of adapter selected by RSSI to the target.
+	adapters []*Adapter
+	logger   *slog.Logger
+	timeouts Timeouts
+	retry    *RetryPolicy
+	framer   Framer
+	cache    *DeviceCache
+	battery  *batteryRecorder
+	aliases  Aliases
+
+	audit     AuditRecorder
+	initiator string
+
+	minRSSI    int16
+	models     []string
+	addrPrefix string
+	duplicates bool
+	params     bluetooth.ConnectionParams
+}
+
+// WithAdapter uses adapter instead of adapters set by SetAdapters or SetTransport.
+func WithAdapter(adapter *Adapter) Option {
+	return WithTransport(adapter.transport)
+}
+
+// WithAdapters uses adapters instead of adapters set by SetAdapters or SetTransport.
+// Connect functions use the adapter which receives the strongest RSSI from the target,
+// and ScanWithOptions uses the first adapter.
+func WithAdapters(as ...*Adapter) Option {
+	return func(o *options) {
+		if len(as) == 0 {
+			return
+		}
+		o.transport = as[0].transport
+		o.adapters = as
+	}
+}
+
+// WithTransport uses t instead of adapters set by SetAdapters or SetTransport.
+func WithTransport(t Transport) Option {
+	return func(o *options) {
+		o.transport = t
+		o.adapters = nil
+	}
+}
+
+// WithLogger uses l instead of logger set by SetLogger. If l is nil, logging is disabled.
+func WithLogger(l *slog.Logger) Option {
+	return func(o *options) {
+		if l == nil {
+			l = newDiscardLogger()
+		}
+		o.logger = l
+	}
+}
+
+// WithScanTimeout sets deadline of scan. If d is zero, deadline set by SetTimeouts is kept.
+func WithScanTimeout(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.timeouts.Scan = d
+		}
+	}
+}
+
+// WithTimeouts sets deadlines of every phase instead of deadlines set by SetTimeouts.
+func WithTimeouts(t Timeouts) Option {
+	return func(o *options) {
+		o.timeouts = t
+	}
+}
+
+// WithPassword frames commands of connected SwitchBot with password.
+func WithPassword(pw string) Option {
+	return WithFramer(NewPasswordFramer(pw))
+}
+
+// WithFramer frames commands of connected SwitchBot with framer.
+func WithFramer(framer Framer) Option {
+	return func(o *options) {
+		o.framer = framer
+	}
+}
+
+// WithRetryPolicy retries scan and connection by p until SwitchBot is connected.
+func WithRetryPolicy(p *RetryPolicy) Option {
+	return func(o *options) {
+		o.retry = p
+	}
+}
+
+// WithDeviceCache remembers SwitchBots found by scan in c, and connects to SwitchBot
+// remembered in c directly before scanning. If c is nil, the cache is disabled.
+func WithDeviceCache(c *DeviceCache) Option {
+	return func(o *options) {
+		o.cache = c
+	}
+}
+
+// WithBatteryRecorder records battery levels obtained from GetInfo and advertisements to r
+// instead of BatteryRecorder set by SetBatteryRecorder. If r is nil, battery levels are not recorded.
+// Unchanged levels from advertisements are recorded once an hour per option,
+// so give it to NewClient rather than to each call.
+func WithBatteryRecorder(r BatteryRecorder) Option {
+	br := newBatteryRecorder(r)
+	return func(o *options) {
+		o.battery = br
+	}
+}
+
+// WithAuditRecorder appends actions such as Press, On and Off of connected SwitchBots to r
+// with initiator, instead of AuditRecorder set by SetAuditRecorder.
+// If r is nil, actions are not recorded.
+func WithAuditRecorder(r AuditRecorder, initiator string) Option {
+	return func(o *options) {
+		o.audit = r
+		o.initiator = initiator
+	}
+}
+
+// WithAliases resolves addr argument with a instead of aliases set by SetAliases.
+func WithAliases(a Aliases) Option {
+	return func(o *options) {
+		o.aliases = a
+	}
+}
+
+// WithMinRSSI ignores advertisements whose RSSI is weaker than rssi dBm.
+// Zero disables the threshold.
+func WithMinRSSI(rssi int16) Option {
+	return func(o *options) {
+		o.minRSSI = rssi
+	}
+}
+
+// WithModels makes ScanWithOptions report only SwitchBots of models named names.
+func WithModels(names ...string) Option {
+	return func(o *options) {
+		o.models = names
+	}
+}
+
+// WithAddressPrefix makes ScanWithOptions report only SwitchBots whose address starts with prefix.
+// Case and separators of prefix are ignored, such as "aa-bb" matches "AA:BB:CC:DD:EE:FF".
+func WithAddressPrefix(prefix string) Option {
+	return func(o *options) {
+		o.addrPrefix = prefix
+	}
+}
+
+// WithDuplicates makes ScanWithOptions call callback with every advertisement
+// instead of once per SwitchBot. It is required to tell LastSeen and SeenCount of ScanResult.
+func WithDuplicates(allow bool) Option {
+	return func(o *options) {
+		o.duplicates = allow
+	}
+}
+
+// WithConnectionParams connects with params if Transport implements ParamsConnector.
+func WithConnectionParams(params bluetooth.ConnectionParams) Option {
+	return func(o *options) {
+		o.params = params
+	}
+}
+
+// accept reports whether adv is strong enough.
+func (o *options) accept(adv *Advertisement) bool {
+	return o.minRSSI == 0 || adv.RSSI >= o.minRSSI
+}
+
+// report reports whether ScanWithOptions reports SwitchBot of model which sent adv.
+func (o *options) report(adv *Advertisement, model *Model) bool {
+	return containsModel(o.models, model) && hasAddressPrefix(adv.Addr, o.addrPrefix)
+}
+
+// enable enables Transport.
+func (o *options) enable() error {
+	return o.transport.Enable()
+}
+
+// remember puts adv to device cache if the cache is enabled.
+func (o *options) remember(adv *Advertisement) {
+	if o.cache != nil && !adv.cached {
+		o.cache.Put(adv)
+	}
+}
+
+// auditor returns auditor of audit recorder, or nil if the recorder is not set.
+func (o *options) auditor() *auditor {
+	if o.audit == nil {
+		return nil
+	}
+	return &auditor{r: o.audit, initiator: o.initiator, aliases: o.aliases}
+}
+
+// resolve parses addr as address, or resolves it as alias of o.
+func (o *options) resolve(addr string) (Address, error) {
+	return resolveAddress(addr, o.aliases)
+}
+
+// connect connects to addr by Transport with connection parameters.
+func (o *options) connect(addr string) (Peripheral, error) {
+	if pc, ok := o.transport.(ParamsConnector); ok {
+		return pc.ConnectWithParams(addr, o.params)
+	}
+	if o.params != (bluetooth.ConnectionParams{}) {
+		o.logger.Warn("connection parameters are not supported by transport", "addr", addr)
+	}
+	return o.transport.Connect(addr)
+}
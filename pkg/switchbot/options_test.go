@@ -0,0 +1,153 @@
+package switchbot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"tinygo.org/x/bluetooth"
+)
+
+// flakyTransport fails Connect until fails reaches zero and records connection parameters.
+type flakyTransport struct {
+	*fakeTransport
+	fails  int
+	params bluetooth.ConnectionParams
+}
+
+func (t *flakyTransport) ConnectWithParams(addr string, params bluetooth.ConnectionParams) (Peripheral, error) {
+	t.mu.Lock()
+	t.params = params
+	fail := t.fails > 0
+	t.fails--
+	t.mu.Unlock()
+
+	if fail {
+		return nil, errors.New("connection refused")
+	}
+	return t.fakeTransport.Connect(addr)
+}
+
+func TestConnectWithOptions(t *testing.T) {
+	ft := &flakyTransport{fakeTransport: newFakeBotTransport(), fails: 1}
+	var buf bytes.Buffer
+	params := bluetooth.ConnectionParams{MinInterval: bluetooth.NewDuration(15 * time.Millisecond)}
+
+	bot, err := ConnectWithOptions(context.Background(), "11:22:33:44:55:66",
+		WithTransport(ft),
+		WithLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+		WithPassword("secret"),
+		WithRetryPolicy(&RetryPolicy{MaxAttempts: 2, Interval: time.Millisecond}),
+		WithConnectionParams(params),
+		WithScanTimeout(time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bot.Disconnect()
+
+	if ft.params != params {
+		t.Errorf("connection params = %+v, want %+v", ft.params, params)
+	}
+	if err := bot.Press(true); err != nil {
+		t.Fatal(err)
+	}
+	writes := ft.written()
+	want, _ := NewPasswordFramer("secret").Frame([]byte{0x57, 0x01})
+	if len(writes) != 1 || !bytes.Equal(writes[0], want) {
+		t.Errorf("written %x, want %x", writes, want)
+	}
+	if !strings.Contains(buf.String(), "failed to connect") || !strings.Contains(buf.String(), "retrying") ||
+		!strings.Contains(buf.String(), "wrote command") {
+		t.Errorf("expected logs to be written to the logger, got %q", buf.String())
+	}
+}
+
+func TestConnectWithOptionsMinRSSI(t *testing.T) {
+	ft := newFakeBotTransport()
+	ft.adv.RSSI = -80
+
+	_, err := ConnectWithOptions(context.Background(), "11:22:33:44:55:66",
+		WithTransport(ft), WithMinRSSI(-70), WithScanTimeout(10*time.Millisecond))
+	var terr *TimeoutError
+	if !errors.As(err, &terr) || terr.Phase != PhaseScan {
+		t.Fatalf("expected TimeoutError of scan, got %v", err)
+	}
+
+	bot, err := ConnectWithOptions(context.Background(), "11:22:33:44:55:66",
+		WithTransport(ft), WithMinRSSI(-90), WithScanTimeout(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bot.Disconnect()
+}
+
+func TestScanWithOptions(t *testing.T) {
+	ft := newFakeBotTransport()
+	ft.adv.RSSI = -60
+	ft.others = []*Advertisement{
+		{Addr: "11:22:33:44:55:66", RSSI: -61, LocalName: "WoHand", ServiceData: []byte{0x48, 0x90, 0xcf}},
+		{Addr: "AA:BB:CC:DD:EE:FF", RSSI: -90, LocalName: "WoHand", ServiceData: []byte{0x48, 0x90, 0xcf}},
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{"default", nil, []string{"11:22:33:44:55:66", "AA:BB:CC:DD:EE:FF"}},
+		{"duplicates", []Option{WithDuplicates(true)}, []string{"11:22:33:44:55:66", "11:22:33:44:55:66", "AA:BB:CC:DD:EE:FF"}},
+		{"min rssi", []Option{WithMinRSSI(-70)}, []string{"11:22:33:44:55:66"}},
+		{"address prefix", []Option{WithAddressPrefix("aa-bb")}, []string{"AA:BB:CC:DD:EE:FF"}},
+		{"other model", []Option{WithModels("PlugMini")}, nil},
+		{"bot", []Option{WithModels("PlugMini", "Bot")}, []string{"11:22:33:44:55:66", "AA:BB:CC:DD:EE:FF"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			opts := append([]Option{WithTransport(ft), WithScanTimeout(10 * time.Millisecond)}, tt.opts...)
+			err := ScanWithOptions(context.Background(), func(res *ScanResult) {
+				got = append(got, res.Addr)
+			}, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("found %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanWithOptionsSeenCount(t *testing.T) {
+	ft := newFakeBotTransport()
+	ft.adv.AddressType = AddressTypeRandom
+	ft.others = []*Advertisement{ft.adv, ft.adv}
+
+	var results []*ScanResult
+	err := ScanWithOptions(context.Background(), func(res *ScanResult) {
+		results = append(results, res)
+	}, WithTransport(ft), WithDuplicates(true), WithScanTimeout(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	first, last := results[0], results[2]
+	if first.SeenCount != 1 || last.SeenCount != 3 {
+		t.Errorf("seen counts = %d and %d, want 1 and 3", first.SeenCount, last.SeenCount)
+	}
+	if !last.FirstSeen.Equal(first.FirstSeen) || last.LastSeen.Before(first.LastSeen) {
+		t.Errorf("unexpected seen times %v-%v", last.FirstSeen, last.LastSeen)
+	}
+	if last.AddressType != AddressTypeRandom || last.LocalName != "WoHand" || !bytes.Equal(last.ServiceData, []byte{0x48, 0x90, 0xcf}) {
+		t.Errorf("unexpected advertisement fields %+v", last)
+	}
+}
@@ -0,0 +1,92 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// PlugMini represents SwitchBot Plug Mini device.
+type PlugMini struct {
+	Addr string
+
+	conn
+}
+
+// Commands of Plug Mini, which are registered as Model.Commands as well.
+var (
+	plugOn     = []byte{0x57, 0x0f, 0x50, 0x01, 0x01, 0x80}
+	plugOff    = []byte{0x57, 0x0f, 0x50, 0x01, 0x01, 0x00}
+	plugToggle = []byte{0x57, 0x0f, 0x50, 0x01, 0x02, 0x80}
+)
+
+func init() {
+	mustRegisterModel(&Model{
+		Name:       "PlugMini",
+		ModelBytes: []byte{'g', 'j'},
+		Decoder: func(mfrData, _ []byte) (interface{}, error) {
+			return NewPlugMiniInfoWithAdvertisement(mfrData)
+		},
+		Commands: map[string][]byte{
+			"on":     plugOn,
+			"off":    plugOff,
+			"toggle": plugToggle,
+		},
+	})
+}
+
+// NewPlugMini initializes plug mini object.
+func NewPlugMini(addr string) *PlugMini {
+	return &PlugMini{Addr: normalizeAddr(addr), conn: newConn(addr)}
+}
+
+// ConnectPlugMini connects to SwitchBot Plug Mini filter by addr argument.
+// If connection failed within timeout, ConnectPlugMini returns error.
+func ConnectPlugMini(ctx context.Context, addr string, timeout time.Duration) (*PlugMini, error) {
+	return defaultClient.ConnectPlugMini(ctx, addr, WithScanTimeout(timeout))
+}
+
+// ConnectPlugMini connects to SwitchBot Plug Mini filter by addr argument with opts.
+func (c *Client) ConnectPlugMini(ctx context.Context, addr string, opts ...Option) (*PlugMini, error) {
+	res, cn, err := c.connect(ctx, addr, opts, "PlugMini")
+	if err != nil {
+		return nil, err
+	}
+	return &PlugMini{Addr: res.Addr, conn: cn}, nil
+}
+
+// GetPlugMiniInfo retrieves Plug Mini's current state from its advertisement.
+// If advertisement is not received within timeout, GetPlugMiniInfo returns error.
+func GetPlugMiniInfo(ctx context.Context, addr string, timeout time.Duration) (*PlugMiniInfo, error) {
+	return defaultClient.GetPlugMiniInfo(ctx, addr, WithScanTimeout(timeout))
+}
+
+// GetPlugMiniInfo retrieves Plug Mini's current state from its advertisement with opts.
+func (c *Client) GetPlugMiniInfo(ctx context.Context, addr string, opts ...Option) (*PlugMiniInfo, error) {
+	res, err := c.scanAddr(ctx, addr, opts, "PlugMini")
+	if err != nil {
+		return nil, err
+	}
+	if res.ManufacturerData == nil {
+		return nil, errors.New("manufacturer data is not found in advertisement")
+	}
+	return NewPlugMiniInfoWithAdvertisement(res.ManufacturerData)
+}
+
+// On turns on the Plug Mini.
+func (p *PlugMini) On(wait bool) error {
+	_, err := p.act("on", plugOn, wait)
+	return err
+}
+
+// Off turns off the Plug Mini.
+func (p *PlugMini) Off(wait bool) error {
+	_, err := p.act("off", plugOff, wait)
+	return err
+}
+
+// Toggle toggles power state of the Plug Mini.
+func (p *PlugMini) Toggle(wait bool) error {
+	_, err := p.act("toggle", plugToggle, wait)
+	return err
+}
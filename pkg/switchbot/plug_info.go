@@ -0,0 +1,44 @@
+package switchbot
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// PlugMiniInfo represents current SwitchBot Plug Mini's information.
+type PlugMiniInfo struct {
+	On       bool    `json:"on"`
+	Load     float64 `json:"load"`
+	Overload bool    `json:"overload"`
+	WiFiRSSI int     `json:"wifi_rssi"`
+}
+
+// NewPlugMiniInfoWithAdvertisement initialize PlugMiniInfo with manufacturer data
+// of Plug Mini's advertisement.
+func NewPlugMiniInfoWithAdvertisement(data []byte) (*PlugMiniInfo, error) {
+	if len(data) < 12 {
+		return nil, fmt.Errorf("plug mini advertisement is too short: %d bytes", len(data))
+	}
+
+	on := data[7] == 0x80
+	rssi := -int(data[9])
+	load := float64(int(data[10]&0x7f)<<8|int(data[11])) / 10
+	ol := (data[10] & 0x80) != 0
+
+	return &PlugMiniInfo{
+		On:       on,
+		Load:     load,
+		Overload: ol,
+		WiFiRSSI: rssi,
+	}, nil
+}
+
+// String returns formatted information
+func (i *PlugMiniInfo) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("On: %t", i.On))
+	buf.WriteString(fmt.Sprintf(", Load: %0.1f", i.Load))
+	buf.WriteString(fmt.Sprintf(", Overload: %t", i.Overload))
+	buf.WriteString(fmt.Sprintf(", WiFiRSSI: %d", i.WiFiRSSI))
+	return buf.String()
+}
@@ -0,0 +1,32 @@
+package switchbot
+
+import "testing"
+
+func TestNewPlugMiniInfoWithAdvertisement(t *testing.T) {
+	r := []byte{0x60, 0x55, 0xf9, 0x11, 0x22, 0x33, 0x2c, 0x80, 0x00, 0x3a, 0x80, 0x7b}
+
+	got, err := NewPlugMiniInfoWithAdvertisement(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.On != true {
+		t.Errorf("On is not correct, got %v", got.On)
+	}
+	if got.Load != 12.3 {
+		t.Errorf("Load is not correct, got %v", got.Load)
+	}
+	if got.Overload != true {
+		t.Errorf("Overload is not correct, got %v", got.Overload)
+	}
+	if got.WiFiRSSI != -58 {
+		t.Errorf("WiFiRSSI is not correct, got %v", got.WiFiRSSI)
+	}
+}
+
+func TestNewPlugMiniInfoWithShortAdvertisement(t *testing.T) {
+	r := []byte{0x60, 0x55, 0xf9, 0x11, 0x22, 0x33, 0x2c, 0x00}
+
+	if _, err := NewPlugMiniInfoWithAdvertisement(r); err == nil {
+		t.Fatal("expected error, but got nil")
+	}
+}
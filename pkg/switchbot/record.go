@@ -0,0 +1,224 @@
+package switchbot
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"tinygo.org/x/bluetooth"
+)
+
+// EventType represents type of recorded BLE event.
+type EventType string
+
+// EventType values recorded by RecordingTransport.
+const (
+	EventAdvertisement  EventType = "advertisement"
+	EventConnect        EventType = "connect"
+	EventService        EventType = "service"
+	EventCharacteristic EventType = "characteristic"
+	EventWrite          EventType = "write"
+	EventNotification   EventType = "notification"
+	EventRead           EventType = "read"
+	EventDisconnect     EventType = "disconnect"
+)
+
+// HexBytes is []byte which is encoded as hex string in JSON.
+type HexBytes []byte
+
+// MarshalText implements encoding.TextMarshaler.
+func (b HexBytes) MarshalText() ([]byte, error) {
+	return []byte(hex.EncodeToString(b)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (b *HexBytes) UnmarshalText(text []byte) error {
+	bs, err := hex.DecodeString(string(text))
+	if err != nil {
+		return err
+	}
+	*b = bs
+	return nil
+}
+
+// Event represents a BLE event recorded by RecordingTransport.
+// Events are written as JSON lines.
+type Event struct {
+	Time time.Time `json:"time"`
+	Type EventType `json:"type"`
+	Addr string    `json:"addr"`
+
+	Service        string   `json:"service,omitempty"`
+	Characteristic string   `json:"characteristic,omitempty"`
+	Data           HexBytes `json:"data,omitempty"`
+
+	AddressType      string   `json:"address_type,omitempty"`
+	RSSI             int16    `json:"rssi,omitempty"`
+	LocalName        string   `json:"local_name,omitempty"`
+	ManufacturerData HexBytes `json:"manufacturer_data,omitempty"`
+	ServiceData      HexBytes `json:"service_data,omitempty"`
+}
+
+// RecordingTransport is Transport which records every advertisement,
+// connection, GATT write and notification of underlying Transport.
+type RecordingTransport struct {
+	Transport
+
+	mu  sync.Mutex
+	enc *json.Encoder
+	now func() time.Time
+}
+
+// NewRecordingTransport initializes RecordingTransport which records events of t to w.
+func NewRecordingTransport(t Transport, w io.Writer) *RecordingTransport {
+	return &RecordingTransport{
+		Transport: t,
+		enc:       json.NewEncoder(w),
+		now:       time.Now,
+	}
+}
+
+func (t *RecordingTransport) record(ev *Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ev.Time = t.now()
+	// Recording is best effort, failure of recording must not break the session.
+	_ = t.enc.Encode(ev)
+}
+
+// Scan records every advertisement and passes it to callback.
+func (t *RecordingTransport) Scan(callback func(adv *Advertisement)) error {
+	return t.Transport.Scan(func(adv *Advertisement) {
+		t.record(&Event{
+			Type:             EventAdvertisement,
+			Addr:             adv.Addr,
+			AddressType:      adv.AddressType,
+			RSSI:             adv.RSSI,
+			LocalName:        adv.LocalName,
+			ManufacturerData: adv.ManufacturerData,
+			ServiceData:      adv.ServiceData,
+		})
+		callback(adv)
+	})
+}
+
+// Connect records connection and returns Peripheral which records GATT events.
+func (t *RecordingTransport) Connect(addr string) (Peripheral, error) {
+	p, err := t.Transport.Connect(addr)
+	if err != nil {
+		return nil, err
+	}
+	t.record(&Event{Type: EventConnect, Addr: addr})
+	return &recordingPeripheral{Peripheral: p, t: t, addr: addr}, nil
+}
+
+// ConnectWithParams is Connect with params. params are ignored unless
+// underlying Transport implements ParamsConnector.
+func (t *RecordingTransport) ConnectWithParams(addr string, params bluetooth.ConnectionParams) (Peripheral, error) {
+	pc, ok := t.Transport.(ParamsConnector)
+	if !ok {
+		return t.Connect(addr)
+	}
+	p, err := pc.ConnectWithParams(addr, params)
+	if err != nil {
+		return nil, err
+	}
+	t.record(&Event{Type: EventConnect, Addr: addr})
+	return &recordingPeripheral{Peripheral: p, t: t, addr: addr}, nil
+}
+
+type recordingPeripheral struct {
+	Peripheral
+
+	t    *RecordingTransport
+	addr string
+}
+
+func (p *recordingPeripheral) DiscoverServices(uuids []bluetooth.UUID) ([]Service, error) {
+	srvcs, err := p.Peripheral.DiscoverServices(uuids)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]Service, 0, len(srvcs))
+	for _, s := range srvcs {
+		p.t.record(&Event{Type: EventService, Addr: p.addr, Service: s.UUID().String()})
+		ret = append(ret, &recordingService{Service: s, p: p})
+	}
+	return ret, nil
+}
+
+func (p *recordingPeripheral) Disconnect() error {
+	p.t.record(&Event{Type: EventDisconnect, Addr: p.addr})
+	return p.Peripheral.Disconnect()
+}
+
+type recordingService struct {
+	Service
+
+	p *recordingPeripheral
+}
+
+func (s *recordingService) DiscoverCharacteristics(uuids []bluetooth.UUID) ([]Characteristic, error) {
+	chars, err := s.Service.DiscoverCharacteristics(uuids)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]Characteristic, 0, len(chars))
+	for _, c := range chars {
+		s.p.t.record(&Event{
+			Type:           EventCharacteristic,
+			Addr:           s.p.addr,
+			Service:        s.UUID().String(),
+			Characteristic: c.UUID().String(),
+		})
+		ret = append(ret, &recordingCharacteristic{Characteristic: c, s: s})
+	}
+	return ret, nil
+}
+
+type recordingCharacteristic struct {
+	Characteristic
+
+	s *recordingService
+}
+
+func (c *recordingCharacteristic) event(typ EventType, data []byte) *Event {
+	return &Event{
+		Type:           typ,
+		Addr:           c.s.p.addr,
+		Service:        c.s.UUID().String(),
+		Characteristic: c.UUID().String(),
+		Data:           append(HexBytes{}, data...),
+	}
+}
+
+func (c *recordingCharacteristic) WriteWithoutResponse(p []byte) (int, error) {
+	c.s.p.t.record(c.event(EventWrite, p))
+	return c.Characteristic.WriteWithoutResponse(p)
+}
+
+func (c *recordingCharacteristic) EnableNotifications(callback func(buf []byte)) error {
+	return c.Characteristic.EnableNotifications(func(buf []byte) {
+		c.s.p.t.record(c.event(EventNotification, buf))
+		callback(buf)
+	})
+}
+
+func (c *recordingCharacteristic) Read(data []byte) (int, error) {
+	n, err := c.Characteristic.Read(data)
+	if err == nil {
+		c.s.p.t.record(c.event(EventRead, data[:n]))
+	}
+	return n, err
+}
+
+func (c *recordingCharacteristic) Properties() ([]string, error) {
+	if cp, ok := c.Characteristic.(characteristicProperties); ok {
+		return cp.Properties()
+	}
+	return nil, errors.New("GATT properties are not available")
+}
@@ -0,0 +1,148 @@
+package switchbot
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+)
+
+// Decoder decodes manufacturer data and service data of advertisement
+// to model specific state, such as *PlugMiniInfo.
+type Decoder func(mfrData, svcData []byte) (interface{}, error)
+
+// ResponseDecoder decodes notification received for a command
+// to model specific information, such as *BotInfo.
+type ResponseDecoder func(res []byte) (interface{}, error)
+
+// Model represents a SwitchBot model which is registered by RegisterModel.
+type Model struct {
+	// Name is the name of the model, such as "Bot".
+	Name string
+
+	// ModelBytes are first bytes of service data which identify the model.
+	ModelBytes []byte
+
+	// LocalNames are local name patterns advertised by the model.
+	// Pattern syntax is the same as path.Match.
+	LocalNames []string
+
+	// Decoder decodes advertisement of the model. Decoder can be nil.
+	Decoder Decoder
+
+	// Commands are commands supported by the model, keyed by command name.
+	// Commands are executed by Device.Exec.
+	Commands map[string][]byte
+
+	// ResponseDecoders decode notifications, keyed by hex encoded prefix of
+	// the command before framing, such as "5702".
+	ResponseDecoders map[string]ResponseDecoder
+}
+
+// DecodeResponse decodes res received for cmd with ResponseDecoder whose key
+// is the longest prefix of cmd.
+// If no ResponseDecoder matches, DecodeResponse returns nil.
+func (m *Model) DecodeResponse(cmd, res []byte) (interface{}, error) {
+	hcmd := hex.EncodeToString(cmd)
+
+	var key string
+	for k := range m.ResponseDecoders {
+		if strings.HasPrefix(hcmd, strings.ToLower(k)) && len(k) > len(key) {
+			key = k
+		}
+	}
+	if key == "" {
+		return nil, nil
+	}
+	return m.ResponseDecoders[key](res)
+}
+
+var registry struct {
+	sync.RWMutex
+	models []*Model
+}
+
+// RegisterModel registers model so that Scan and ConnectDevice can identify it.
+// It returns error if a model with the same name is already registered.
+func RegisterModel(m *Model) error {
+	if m == nil || m.Name == "" {
+		return errors.New("model name must not be empty")
+	}
+	for _, pat := range m.LocalNames {
+		if _, err := path.Match(pat, ""); err != nil {
+			return fmt.Errorf("invalid local name pattern %q: %w", pat, err)
+		}
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	for _, rm := range registry.models {
+		if rm.Name == m.Name {
+			return fmt.Errorf("model %q is already registered", m.Name)
+		}
+	}
+	registry.models = append(registry.models, m)
+	return nil
+}
+
+// Models returns all registered models.
+func Models() []*Model {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	return append([]*Model{}, registry.models...)
+}
+
+// LookupModel returns registered model by name.
+// If model is not registered, it returns nil.
+func LookupModel(name string) *Model {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	for _, m := range registry.models {
+		if m.Name == name {
+			return m
+		}
+	}
+	return nil
+}
+
+func mustRegisterModel(m *Model) {
+	if err := RegisterModel(m); err != nil {
+		panic(err)
+	}
+}
+
+// matchModel returns registered model which matches advertisement.
+// Model byte of service data takes precedence over local name.
+func matchModel(adv *Advertisement) *Model {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	if len(adv.ServiceData) != 0 {
+		// Most significant bit of model byte represents encryption of the device.
+		mb := adv.ServiceData[0] & 0x7f
+		for _, m := range registry.models {
+			for _, b := range m.ModelBytes {
+				if b == mb {
+					return m
+				}
+			}
+		}
+	}
+
+	if adv.LocalName != "" {
+		for _, m := range registry.models {
+			for _, pat := range m.LocalNames {
+				if ok, _ := path.Match(pat, adv.LocalName); ok {
+					return m
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package switchbot
+
+import "testing"
+
+func TestRegisterModel(t *testing.T) {
+	m := &Model{
+		Name:       "TestRegisterModel",
+		ModelBytes: []byte{0x7e},
+		LocalNames: []string{"WoTest*"},
+	}
+	if err := RegisterModel(m); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		unregisterTestModel(m.Name)
+	})
+	if got := LookupModel("TestRegisterModel"); got != m {
+		t.Errorf("LookupModel expected %v, got %v", m, got)
+	}
+	if err := RegisterModel(&Model{Name: "TestRegisterModel"}); err == nil {
+		t.Error("expected error for duplicated model, but got nil")
+	}
+}
+
+func TestRegisterModelWithInvalidModel(t *testing.T) {
+	tests := []*Model{
+		nil,
+		{Name: ""},
+		{Name: "TestInvalidPattern", LocalNames: []string{"["}},
+	}
+
+	for _, m := range tests {
+		if err := RegisterModel(m); err == nil {
+			t.Errorf("expected error for %v, but got nil", m)
+		}
+	}
+}
+
+func TestMatchModel(t *testing.T) {
+	tests := []struct {
+		adv  *Advertisement
+		want string
+	}{
+		{
+			adv:  &Advertisement{ServiceData: []byte{'H', 0x90, 0x51}},
+			want: "Bot",
+		},
+		{
+			adv:  &Advertisement{ServiceData: []byte{'g' | 0x80}},
+			want: "PlugMini",
+		},
+		{
+			adv:  &Advertisement{LocalName: "WoHand"},
+			want: "Bot",
+		},
+		{
+			adv:  &Advertisement{ServiceData: []byte{'x'}, LocalName: "WoHand"},
+			want: "BlindTilt",
+		},
+	}
+
+	for _, tt := range tests {
+		got := matchModel(tt.adv)
+		if got == nil || got.Name != tt.want {
+			t.Errorf("matchModel(%+v) expected %s, got %v", tt.adv, tt.want, got)
+		}
+	}
+}
+
+func TestMatchModelReturnsNil(t *testing.T) {
+	adv := &Advertisement{ServiceData: []byte{0x01}, LocalName: "Unknown"}
+	if got := matchModel(adv); got != nil {
+		t.Fatal("expected nil, but got", got)
+	}
+}
+
+func unregisterTestModel(name string) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	for i, m := range registry.models {
+		if m.Name == name {
+			registry.models = append(registry.models[:i], registry.models[i+1:]...)
+			return
+		}
+	}
+}
+
+func TestModelDecodeResponse(t *testing.T) {
+	m := LookupModel("Bot")
+
+	got, err := m.DecodeResponse([]byte{0x57, 0x02}, []byte{1, 79, 45, 100, 0, 0, 0, 152, 3, 0, 3, 72, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, ok := got.(*BotInfo)
+	if !ok || info.Battery != 79 {
+		t.Errorf("unexpected decoded response %v", got)
+	}
+
+	got, err = m.DecodeResponse([]byte{0x57, 0x01}, []byte{1})
+	if err != nil || got != nil {
+		t.Errorf("expected nil for unknown command, got %v, %v", got, err)
+	}
+}
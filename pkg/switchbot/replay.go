@@ -0,0 +1,276 @@
+package switchbot
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"tinygo.org/x/bluetooth"
+)
+
+// ReplayTransport is Transport which replays events recorded by RecordingTransport.
+// Advertisements are replayed by Scan, and recorded notifications are replayed
+// when the same bytes as recorded are written to the characteristic.
+// Timing of recorded events is ignored to keep replay deterministic.
+type ReplayTransport struct {
+	events []*Event
+
+	mu      sync.Mutex
+	stop    chan struct{}
+	pos     int
+	reads   map[string]int
+	notifys map[string]func(buf []byte)
+}
+
+// NewReplayTransport initializes ReplayTransport with events read from r.
+func NewReplayTransport(r io.Reader) (*ReplayTransport, error) {
+	t := &ReplayTransport{
+		reads:   make(map[string]int),
+		notifys: make(map[string]func(buf []byte)),
+	}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+		ev := &Event{}
+		if err := json.Unmarshal(sc.Bytes(), ev); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		t.events = append(t.events, ev)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Enable does nothing.
+func (t *ReplayTransport) Enable() error {
+	return nil
+}
+
+// Scan replays recorded advertisements and blocks until StopScan is called.
+func (t *ReplayTransport) Scan(callback func(adv *Advertisement)) error {
+	stop := make(chan struct{})
+	t.mu.Lock()
+	t.stop = stop
+	t.mu.Unlock()
+
+	for _, ev := range t.events {
+		if ev.Type != EventAdvertisement {
+			continue
+		}
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+		callback(&Advertisement{
+			Addr:             ev.Addr,
+			AddressType:      ev.AddressType,
+			RSSI:             ev.RSSI,
+			LocalName:        ev.LocalName,
+			ManufacturerData: ev.ManufacturerData,
+			ServiceData:      ev.ServiceData,
+		})
+	}
+
+	<-stop
+	return nil
+}
+
+// StopScan stops Scan.
+func (t *ReplayTransport) StopScan() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.stop != nil {
+		close(t.stop)
+		t.stop = nil
+	}
+	return nil
+}
+
+// Connect returns Peripheral if connection to addr is recorded.
+func (t *ReplayTransport) Connect(addr string) (Peripheral, error) {
+	for _, ev := range t.events {
+		if ev.Type == EventConnect && strings.EqualFold(ev.Addr, addr) {
+			return &replayPeripheral{t: t, addr: ev.Addr}, nil
+		}
+	}
+	return nil, fmt.Errorf("connection to %s is not recorded", addr)
+}
+
+// write replays notifications recorded after the write of p.
+func (t *ReplayTransport) write(addr, char string, p []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	idx := -1
+	for i := t.pos; i < len(t.events); i++ {
+		ev := t.events[i]
+		if ev.Type == EventWrite && ev.Addr == addr {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return fmt.Errorf("write %x to %s is not recorded", p, char)
+	}
+	if ev := t.events[idx]; ev.Characteristic != char || !bytes.Equal(ev.Data, p) {
+		return fmt.Errorf("write %x to %s does not match recorded write %x to %s", p, char, []byte(ev.Data), ev.Characteristic)
+	}
+	t.pos = idx + 1
+
+	var notifications []*Event
+	for i := t.pos; i < len(t.events); i++ {
+		ev := t.events[i]
+		if ev.Addr != addr {
+			continue
+		}
+		if ev.Type == EventWrite {
+			break
+		}
+		if ev.Type == EventNotification {
+			notifications = append(notifications, ev)
+		}
+	}
+
+	notifys := make([]func(buf []byte), len(notifications))
+	for i, ev := range notifications {
+		notifys[i] = t.notifys[addr+"/"+ev.Characteristic]
+	}
+	go func() {
+		for i, ev := range notifications {
+			if notifys[i] != nil {
+				notifys[i](append([]byte{}, ev.Data...))
+			}
+		}
+	}()
+	return nil
+}
+
+// read returns the next recorded read of the characteristic.
+func (t *ReplayTransport) read(addr, char string, data []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := addr + "/" + char
+	for i := t.reads[key]; i < len(t.events); i++ {
+		ev := t.events[i]
+		if ev.Type == EventRead && ev.Addr == addr && ev.Characteristic == char {
+			t.reads[key] = i + 1
+			return copy(data, ev.Data), nil
+		}
+	}
+	return 0, fmt.Errorf("read of %s is not recorded", char)
+}
+
+type replayPeripheral struct {
+	t    *ReplayTransport
+	addr string
+}
+
+func (p *replayPeripheral) DiscoverServices(uuids []bluetooth.UUID) ([]Service, error) {
+	var ret []Service
+	var founds []string
+	for _, ev := range p.t.events {
+		if ev.Type != EventService || ev.Addr != p.addr || !newlyFoundTarget(founds, ev.Service) {
+			continue
+		}
+		uuid, err := bluetooth.ParseUUID(ev.Service)
+		if err != nil {
+			return nil, err
+		}
+		if !containsUUID(uuids, uuid) {
+			continue
+		}
+		founds = append(founds, ev.Service)
+		ret = append(ret, &replayService{p: p, uuid: uuid})
+	}
+	return ret, nil
+}
+
+func (p *replayPeripheral) Disconnect() error {
+	return nil
+}
+
+type replayService struct {
+	p    *replayPeripheral
+	uuid bluetooth.UUID
+}
+
+func (s *replayService) UUID() bluetooth.UUID {
+	return s.uuid
+}
+
+func (s *replayService) DiscoverCharacteristics(uuids []bluetooth.UUID) ([]Characteristic, error) {
+	var ret []Characteristic
+	var founds []string
+	for _, ev := range s.p.t.events {
+		if ev.Type != EventCharacteristic || ev.Addr != s.p.addr || ev.Service != s.uuid.String() ||
+			!newlyFoundTarget(founds, ev.Characteristic) {
+			continue
+		}
+		uuid, err := bluetooth.ParseUUID(ev.Characteristic)
+		if err != nil {
+			return nil, err
+		}
+		if !containsUUID(uuids, uuid) {
+			continue
+		}
+		founds = append(founds, ev.Characteristic)
+		ret = append(ret, &replayCharacteristic{s: s, uuid: uuid})
+	}
+	return ret, nil
+}
+
+type replayCharacteristic struct {
+	s    *replayService
+	uuid bluetooth.UUID
+}
+
+func (c *replayCharacteristic) UUID() bluetooth.UUID {
+	return c.uuid
+}
+
+func (c *replayCharacteristic) WriteWithoutResponse(p []byte) (int, error) {
+	if err := c.s.p.t.write(c.s.p.addr, c.uuid.String(), p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (c *replayCharacteristic) EnableNotifications(callback func(buf []byte)) error {
+	t := c.s.p.t
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.notifys[c.s.p.addr+"/"+c.uuid.String()] = callback
+	return nil
+}
+
+func (c *replayCharacteristic) Read(data []byte) (int, error) {
+	return c.s.p.t.read(c.s.p.addr, c.uuid.String(), data)
+}
+
+// containsUUID reports whether uuids contains uuid.
+// If uuids is nil, every uuid is contained.
+func containsUUID(uuids []bluetooth.UUID, uuid bluetooth.UUID) bool {
+	if uuids == nil {
+		return true
+	}
+	for _, u := range uuids {
+		if u == uuid {
+			return true
+		}
+	}
+	return false
+}
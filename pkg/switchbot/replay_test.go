@@ -0,0 +1,117 @@
+package switchbot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+)
+
+func newTestReplayTransport(t *testing.T, name string) *ReplayTransport {
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	rt, err := NewReplayTransport(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rt
+}
+
+func useTestTransport(t *testing.T, tr Transport) {
+	orig := CurrentTransport()
+	SetTransport(tr)
+	t.Cleanup(func() {
+		SetTransport(orig)
+	})
+}
+
+func TestReplayScan(t *testing.T) {
+	useTestTransport(t, newTestReplayTransport(t, "testdata/getinfo.jsonl"))
+
+	var results []*ScanResult
+	err := ScanDevices(context.Background(), 100*time.Millisecond, func(res *ScanResult) {
+		results = append(results, res)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	if results[0].Addr != "11:22:33:44:55:66" || results[0].Model.Name != "Bot" {
+		t.Errorf("unexpected result %+v", results[0])
+	}
+	state, ok := results[0].State.(*BotState)
+	if !ok || state.Battery != 79 {
+		t.Errorf("unexpected state %+v", results[0].State)
+	}
+}
+
+func TestReplayGetInfo(t *testing.T) {
+	useTestTransport(t, newTestReplayTransport(t, "testdata/getinfo.jsonl"))
+
+	bot, err := Connect(context.Background(), "11:22:33:44:55:66", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bot.Disconnect()
+
+	info, err := bot.GetInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Battery != 79 {
+		t.Errorf("Battery is not correct, got %v", info.Battery)
+	}
+	if info.Firmware != float64(45)/10 {
+		t.Errorf("Firmware is not correct, got %v", info.Firmware)
+	}
+
+	if err := bot.Press(true); err == nil {
+		t.Error("expected error for unrecorded write, but got nil")
+	}
+}
+
+func TestRecordReplay(t *testing.T) {
+	var buf bytes.Buffer
+	rec := NewRecordingTransport(newTestReplayTransport(t, "testdata/getinfo.jsonl"), &buf)
+	useTestTransport(t, rec)
+
+	bot, err := Connect(context.Background(), "11:22:33:44:55:66", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bot.GetInfo(); err != nil {
+		t.Fatal(err)
+	}
+	bot.Disconnect()
+
+	var types []EventType
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		ev := &Event{}
+		if err := dec.Decode(ev); err != nil {
+			t.Fatal(err)
+		}
+		types = append(types, ev.Type)
+	}
+	want := []EventType{
+		EventAdvertisement, EventConnect, EventService, EventCharacteristic, EventCharacteristic,
+		EventWrite, EventNotification, EventDisconnect,
+	}
+	if len(types) != len(want) {
+		t.Fatalf("recorded events expected %v, got %v", want, types)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Errorf("recorded events expected %v, got %v", want, types)
+			break
+		}
+	}
+}
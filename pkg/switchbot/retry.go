@@ -0,0 +1,145 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand"
+	"time"
+)
+
+// Backoff strategies of RetryPolicy.
+const (
+	// BackoffConstant waits Interval before every retry.
+	BackoffConstant = "constant"
+	// BackoffExponential doubles interval on every retry starting from Interval.
+	BackoffExponential = "exp"
+	// BackoffJitter waits random duration up to interval of BackoffExponential.
+	BackoffJitter = "jitter"
+)
+
+// DefaultRetryInterval is interval before the first retry if RetryPolicy.Interval is zero.
+const DefaultRetryInterval = 1 * time.Second
+
+// RetryPolicy configures retry of connection and command against SwitchBot.
+// WithRetryPolicy retries only scan and connection, so that commands which already
+// took effect are not repeated. Wrap commands by Do to retry them as well.
+// A nil RetryPolicy attempts only once.
+type RetryPolicy struct {
+	// Backoff is BackoffConstant, BackoffExponential or BackoffJitter.
+	// BackoffConstant is used if empty.
+	Backoff string
+	// Interval is interval before the first retry.
+	Interval time.Duration
+	// MaxInterval caps interval of BackoffExponential and BackoffJitter if not zero.
+	MaxInterval time.Duration
+	// MaxAttempts is maximum number of attempts including the first one.
+	// Attempts are not limited by count if zero, but then MaxElapsed must be set to retry.
+	MaxAttempts int
+	// MaxElapsed gives up retry if the next attempt would start after the duration
+	// since the first attempt. Not limited if zero.
+	MaxElapsed time.Duration
+	// Retryable reports whether err is worth retrying. IsRetryable is used if nil.
+	Retryable func(err error) bool
+}
+
+// IsValidBackoff reports whether s is one of the backoff strategies.
+func IsValidBackoff(s string) bool {
+	switch s {
+	case BackoffConstant, BackoffExponential, BackoffJitter:
+		return true
+	}
+	return false
+}
+
+// IsRetryable reports whether err may be resolved by retrying, such as timeout of connection.
+// Errors which are caused by caller or SwitchBot's rejection, such as invalid address,
+// unexpected model, cancellation and CommandError caused by wrong password, are not retryable.
+func IsRetryable(err error) bool {
+	var mismatch *ModelMismatchError
+	var cmdErr *CommandError
+	switch {
+	case err == nil,
+		errors.Is(err, context.Canceled),
+		errors.Is(err, ErrInvalidAddress),
+		errors.Is(err, ErrServiceNotFound),
+		errors.As(err, &mismatch),
+		errors.As(err, &cmdErr):
+		return false
+	}
+	return true
+}
+
+// Do calls f until it succeeds, it returns error which is not retryable or p gives up.
+// Cancellation of ctx stops waiting for the next attempt.
+// The error of the last attempt is returned. Retries are logged by logger set by SetLogger.
+func (p *RetryPolicy) Do(ctx context.Context, f func() error) error {
+	return p.do(ctx, defaultClient.current().logger, f)
+}
+
+// do is Do which logs retries by l.
+func (p *RetryPolicy) do(ctx context.Context, l *slog.Logger, f func() error) error {
+	start := time.Now()
+	for n := 1; ; n++ {
+		err := f()
+		if err == nil || !p.retry(n, start, err) {
+			return err
+		}
+
+		d := p.interval(n)
+		if p.MaxElapsed > 0 && time.Since(start)+d > p.MaxElapsed {
+			return err
+		}
+		l.Debug("retrying", "attempt", n, "interval", d, "error", err)
+
+		t := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+	}
+}
+
+// retry reports whether the next attempt is allowed after n attempts failed with err.
+func (p *RetryPolicy) retry(n int, start time.Time, err error) bool {
+	if p == nil {
+		return false
+	}
+	if p.MaxAttempts > 0 && n >= p.MaxAttempts {
+		return false
+	}
+	if p.MaxAttempts <= 0 && p.MaxElapsed <= 0 {
+		return false
+	}
+
+	retryable := IsRetryable
+	if p.Retryable != nil {
+		retryable = p.Retryable
+	}
+	return retryable(err)
+}
+
+// interval returns duration to wait after n-th attempt.
+func (p *RetryPolicy) interval(n int) time.Duration {
+	d := p.Interval
+	if d <= 0 {
+		d = DefaultRetryInterval
+	}
+	if p.Backoff == "" || p.Backoff == BackoffConstant {
+		return d
+	}
+
+	for i := 1; i < n; i++ {
+		d *= 2
+		if p.MaxInterval > 0 && d >= p.MaxInterval {
+			d = p.MaxInterval
+			break
+		}
+	}
+	if p.Backoff == BackoffJitter {
+		d = time.Duration(rand.Int63n(int64(d) + 1))
+	}
+	return d
+}
@@ -0,0 +1,133 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyDo(t *testing.T) {
+	errTimeout := context.DeadlineExceeded
+	cases := []struct {
+		name     string
+		policy   *RetryPolicy
+		errs     []error
+		attempts int
+		wantErr  error
+	}{
+		{"nil policy", nil, []error{errTimeout, nil}, 1, errTimeout},
+		{"success", &RetryPolicy{MaxAttempts: 3}, []error{nil}, 1, nil},
+		{"retry until success", &RetryPolicy{MaxAttempts: 3}, []error{errTimeout, errTimeout, nil}, 3, nil},
+		{"max attempts", &RetryPolicy{MaxAttempts: 2}, []error{errTimeout, errTimeout, nil}, 2, errTimeout},
+		{"not retryable", &RetryPolicy{MaxAttempts: 3}, []error{&CommandError{Response: []byte{0x07}}, nil}, 1, &CommandError{}},
+		{"custom classifier", &RetryPolicy{MaxAttempts: 3, Retryable: func(error) bool { return false }}, []error{errTimeout, nil}, 1, errTimeout},
+		{"no limit", &RetryPolicy{}, []error{errTimeout, nil}, 1, errTimeout},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.policy != nil {
+				tc.policy.Interval = time.Millisecond
+			}
+			attempts := 0
+			err := tc.policy.Do(context.Background(), func() error {
+				err := tc.errs[attempts]
+				attempts++
+				return err
+			})
+			if attempts != tc.attempts {
+				t.Errorf("attempts = %d, want %d", attempts, tc.attempts)
+			}
+			var cmdErr *CommandError
+			switch {
+			case tc.wantErr == nil && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case errors.As(tc.wantErr, &cmdErr) && !errors.As(err, &cmdErr):
+				t.Errorf("error = %v, want CommandError", err)
+			case tc.wantErr != nil && !errors.As(tc.wantErr, &cmdErr) && !errors.Is(err, tc.wantErr):
+				t.Errorf("error = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyMaxElapsed(t *testing.T) {
+	p := &RetryPolicy{Interval: 20 * time.Millisecond, MaxElapsed: 50 * time.Millisecond}
+	attempts := 0
+	err := p.Do(context.Background(), func() error {
+		attempts++
+		return context.DeadlineExceeded
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v", err)
+	}
+	if attempts < 2 || attempts > 3 {
+		t.Errorf("attempts = %d, want 2 or 3", attempts)
+	}
+}
+
+func TestRetryPolicyCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &RetryPolicy{Interval: time.Hour, MaxAttempts: 3}
+	attempts := 0
+	err := p.Do(ctx, func() error {
+		attempts++
+		cancel()
+		return context.DeadlineExceeded
+	})
+	if !errors.Is(err, context.DeadlineExceeded) || attempts != 1 {
+		t.Errorf("error = %v, attempts = %d", err, attempts)
+	}
+}
+
+func TestRetryPolicyInterval(t *testing.T) {
+	cases := []struct {
+		policy *RetryPolicy
+		want   []time.Duration
+	}{
+		{&RetryPolicy{}, []time.Duration{time.Second, time.Second, time.Second}},
+		{&RetryPolicy{Backoff: BackoffConstant, Interval: 2 * time.Second}, []time.Duration{2 * time.Second, 2 * time.Second, 2 * time.Second}},
+		{&RetryPolicy{Backoff: BackoffExponential}, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}},
+		{&RetryPolicy{Backoff: BackoffExponential, MaxInterval: 3 * time.Second}, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}},
+	}
+
+	for _, tc := range cases {
+		for i, want := range tc.want {
+			if got := tc.policy.interval(i + 1); got != want {
+				t.Errorf("interval(%d) of %+v = %s, want %s", i+1, tc.policy, got, want)
+			}
+		}
+	}
+
+	p := &RetryPolicy{Backoff: BackoffJitter}
+	for n := 1; n <= 3; n++ {
+		max := (&RetryPolicy{Backoff: BackoffExponential}).interval(n)
+		if got := p.interval(n); got < 0 || got > max {
+			t.Errorf("jitter interval(%d) = %s, want up to %s", n, got, max)
+		}
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{context.DeadlineExceeded, true},
+		{errors.New("connection lost"), true},
+		{&DiscoveryError{Addr: "11:22:33:44:55:66", UUID: "cba20002", Err: ErrCharacteristicNotFound}, true},
+		{context.Canceled, false},
+		{fmt.Errorf("%w %q", ErrInvalidAddress, "foo"), false},
+		{&DiscoveryError{Addr: "11:22:33:44:55:66", UUID: "cba20d00", Err: ErrServiceNotFound}, false},
+		{&ModelMismatchError{Addr: "11:22:33:44:55:66", Model: "WoPlug", Expected: []string{"WoHand"}}, false},
+		{&CommandError{Response: []byte{0x07}}, false},
+	}
+
+	for _, tc := range cases {
+		if got := IsRetryable(tc.err); got != tc.want {
+			t.Errorf("IsRetryable(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
@@ -2,7 +2,8 @@ package switchbot
 
 import (
 	"context"
-	"strings"
+	"errors"
+	"sync"
 	"time"
 
 	"tinygo.org/x/bluetooth"
@@ -13,138 +14,501 @@ var (
 	subscribeUUID, _ = bluetooth.ParseUUID("cba20003-224d-11e6-9fb8-0002a5d5c51b")
 	commandUUID, _   = bluetooth.ParseUUID("cba20002-224d-11e6-9fb8-0002a5d5c51b")
 
-	adapter *bluetooth.Adapter
+	// nearestScanWindow is duration to collect candidates of Nearest.
+	nearestScanWindow = 3 * time.Second
 )
 
-func init() {
-	adapter = bluetooth.DefaultAdapter
+// SetTransport sets Transport used by Scan, Connect and devices instead of adapters.
+// By default, Transport built on bluetooth.DefaultAdapter is used.
+func SetTransport(t Transport) {
+	defaultClient.configure(WithTransport(t))
+}
+
+// CurrentTransport returns Transport used by Scan, Connect and devices.
+func CurrentTransport() Transport {
+	return defaultClient.current().transport
+}
+
+// ScanResult represents SwitchBot found by ScanDevices.
+type ScanResult struct {
+	Addr  string
+	RSSI  int
+	Model *Model
+
+	// State is advertisement decoded by Model.Decoder.
+	// State is nil if the model has no decoder or decoding failed.
+	State interface{}
+
+	// AddressType is AddressTypePublic or AddressTypeRandom. Empty if unknown.
+	AddressType string
+	LocalName   string
+	// ManufacturerData is raw manufacturer data of Woan Technology.
+	ManufacturerData []byte
+	// ServiceData is raw service data of SwitchBot service.
+	ServiceData []byte
+
+	// FirstSeen and LastSeen are times when the first and the latest advertisements
+	// of the SwitchBot are received during the scan.
+	// LastSeen equals FirstSeen unless WithDuplicates is given.
+	FirstSeen time.Time
+	LastSeen  time.Time
+	// SeenCount is count of advertisements of the SwitchBot received during the scan.
+	// It is always 1 unless WithDuplicates is given, since callback is called only once.
+	SeenCount int
 }
 
 // Scan scans nearby SwitchBots.
 // Callback function will be executed with MAC address once a SwitchBot is found.
 // If any SwitchBots are not found, it returns nothing(no timeout error).
 func Scan(ctx context.Context, timeout time.Duration, callback func(addr string)) error {
-	if err := adapter.Enable(); err != nil {
+	return ScanDevices(ctx, timeout, func(res *ScanResult) {
+		callback(res.Addr)
+	})
+}
+
+// ScanDevices scans nearby SwitchBots of registered models.
+// Callback function will be executed with ScanResult once a SwitchBot is found.
+// If any SwitchBots are not found, it returns nothing(no timeout error).
+func ScanDevices(ctx context.Context, timeout time.Duration, callback func(res *ScanResult)) error {
+	return ScanWithOptions(ctx, callback, WithScanTimeout(timeout))
+}
+
+// ScanWithOptions scans nearby SwitchBots of registered models until deadline of scan
+// or ctx is done. Callback function will be executed with ScanResult once a SwitchBot
+// is found, or with every advertisement if WithDuplicates is given.
+// If any SwitchBots are not found, it returns nothing(no timeout error).
+func ScanWithOptions(ctx context.Context, callback func(res *ScanResult), opts ...Option) error {
+	return defaultClient.Scan(ctx, callback, opts...)
+}
+
+func scanWithOptions(ctx context.Context, callback func(res *ScanResult), o *options) error {
+	if err := o.enable(); err != nil {
 		return err
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+	timeout := o.timeouts.Scan
+	ctx, cancel := phaseContext(ctx, timeout)
 	defer cancel()
 
-	var err error
-	var founds = make([]string, 0)
+	start := time.Now()
+	o.logger.Debug("scan started", "timeout", timeout)
+
+	// seen maps address to the first seen time and seen count of SwitchBot found during the scan.
+	type sighting struct {
+		first time.Time
+		count int
+		// latest is the latest result, whose battery level is recorded after the scan.
+		latest *ScanResult
+	}
+	seen := make(map[string]*sighting)
+	errc := make(chan error, 1)
 	go func() {
-		err = adapter.Scan(func(a *bluetooth.Adapter, res bluetooth.ScanResult) {
-			addr := res.Address.String()
-			if res.LocalName() == "WoHand" && newlyFoundTarget(founds, addr) {
-				founds = append(founds, addr)
-				callback(addr)
+		errc <- o.transport.Scan(func(adv *Advertisement) {
+			adv.Addr = normalizeAddr(adv.Addr)
+			addr := adv.Addr
+			st, found := seen[addr]
+			if !o.accept(adv) || (!o.duplicates && found) {
+				return
+			}
+			model := matchModel(adv)
+			if model == nil || !o.report(adv, model) {
+				return
 			}
+
+			now := time.Now()
+			if !found {
+				st = &sighting{first: now}
+				seen[addr] = st
+				o.remember(adv)
+			}
+			st.count++
+
+			res := newScanResult(adv, model)
+			res.FirstSeen, res.LastSeen, res.SeenCount = st.first, now, st.count
+			st.latest = res
+			o.logger.Debug("scan hit", "addr", addr, "model", model.Name, "rssi", adv.RSSI)
+			callback(res)
 		})
 	}()
 
-	for {
-		select {
-		case <-ctx.Done():
-			adapter.StopScan()
-			return scanError(ctx.Err())
-		default:
-			if err != nil {
-				adapter.StopScan()
-				return scanError(err)
-			}
-		}
+	var err error
+	select {
+	case <-ctx.Done():
+		o.transport.StopScan()
+		<-errc
+		err = scanError(ctx.Err())
+	case err = <-errc:
+		o.transport.StopScan()
+		err = scanError(err)
+	}
+
+	// Battery levels are recorded once per SwitchBot, not to write every advertisement.
+	for addr, st := range seen {
+		o.battery.record(o.logger, addr, st.latest.Model.Name, BatterySourceAdvertisement, st.latest.State)
+	}
+	if err != nil {
+		o.logger.Warn("scan failed", "error", err, "duration", time.Since(start))
+		return err
 	}
+	o.logger.Info("scan finished", "found", len(seen), "duration", time.Since(start))
+	return nil
 }
 
 // Connect connects to SwitchBot filter by addr argument.
-// If connection failed within timeout, Connect returns error.
+// addr is address, alias set by SetAliases or Nearest.
+// If SwitchBot is not found within timeout, Connect returns TimeoutError.
+// Deadlines of connection, discovery and responses are set by SetTimeouts.
+// If SwitchBot service or its characteristics are not found, Connect returns DiscoveryError.
+// If the SwitchBot is identified as other than Bot, Connect returns ModelMismatchError.
 func Connect(ctx context.Context, addr string, timeout time.Duration) (*Bot, error) {
-	if err := adapter.Enable(); err != nil {
+	return ConnectWithOptions(ctx, addr, WithScanTimeout(timeout))
+}
+
+// ConnectWithOptions connects to Bot filter by addr argument with opts.
+// It returns the same errors as Connect.
+func ConnectWithOptions(ctx context.Context, addr string, opts ...Option) (*Bot, error) {
+	return defaultClient.Connect(ctx, addr, opts...)
+}
+
+// connect scans SwitchBot filter by addr argument and connects to it.
+// Scan and connection are retried by retry policy of o.
+// If models are given, connect fails with ModelMismatchError when found SwitchBot
+// is identified as another model.
+func connect(ctx context.Context, addr string, o *options, models ...string) (*Advertisement, conn, error) {
+	var res *Advertisement
+	var c conn
+	err := o.retry.do(ctx, o.logger, func() error {
+		var err error
+		res, c, err = connectOnce(ctx, addr, o, models...)
+		return err
+	})
+	if err != nil {
+		return nil, conn{}, err
+	}
+	return res, c, nil
+}
+
+// connectOnce scans SwitchBot filter by addr argument and connects to it without retry.
+// SwitchBot remembered by device cache of o is connected directly before scanning.
+func connectOnce(ctx context.Context, addr string, o *options, models ...string) (*Advertisement, conn, error) {
+	start := time.Now()
+	if res, c, ok := connectCached(ctx, addr, o, models); ok {
+		c.logger.Info("connection established", "duration", time.Since(start), "cached", true)
+		return res, c, nil
+	}
+
+	res, o, err := findTarget(ctx, addr, o, models...)
+	if err != nil {
+		o.logger.Warn("target not found", "addr", addr, "error", err, "duration", time.Since(start))
+		return nil, conn{}, err
+	}
+	o.logger.Debug("target found", "addr", res.Addr, "rssi", res.RSSI, "duration", time.Since(start))
+
+	if err := checkModel(res, models); err != nil {
+		o.logger.Warn("unexpected model", "addr", res.Addr, "error", err)
+		return nil, conn{}, err
+	}
+	o.remember(res)
+
+	c, err := establish(ctx, res, o, func() (Peripheral, error) {
+		return o.connect(res.Addr)
+	})
+	if err != nil {
+		return nil, conn{}, err
+	}
+	c.logger.Info("connection established", "duration", time.Since(start))
+	return res, c, nil
+}
+
+// connectCached connects directly to SwitchBot filter by addr argument if device cache of o
+// remembers it and Transport implements DirectConnector. It returns false if the SwitchBot
+// is not remembered or the connection fails, so that caller scans the SwitchBot.
+func connectCached(ctx context.Context, addr string, o *options, models []string) (*Advertisement, conn, bool) {
+	if o.cache == nil || addr == Nearest || len(o.adapters) > 1 {
+		return nil, conn{}, false
+	}
+	dc, ok := o.transport.(DirectConnector)
+	if !ok {
+		return nil, conn{}, false
+	}
+	target, err := o.resolve(addr)
+	if err != nil {
+		return nil, conn{}, false
+	}
+
+	d, ok := o.cache.Get(target)
+	if !ok {
+		o.logger.Debug("device cache miss", "addr", target)
+		return nil, conn{}, false
+	}
+	o.logger.Debug("device cache hit", "addr", target, "rssi", d.RSSI, "last_seen", d.LastSeen)
+
+	res := d.advertisement()
+	if err := checkModel(res, models); err != nil {
+		o.logger.Debug("cached device is another model, scanning", "addr", target, "error", err)
+		return nil, conn{}, false
+	}
+	if err := o.enable(); err != nil {
+		o.logger.Info("failed to enable transport, scanning", "addr", target, "error", err)
+		return nil, conn{}, false
+	}
+	c, err := establish(ctx, res, o, func() (Peripheral, error) {
+		return dc.ConnectDirect(res.Addr, res.AddressType, o.params)
+	})
+	if err != nil {
+		o.logger.Info("direct connection failed, falling back to scan", "addr", target, "error", err)
+		o.cache.fallback(target)
+		return nil, conn{}, false
+	}
+	return res, c, true
+}
+
+// establish connects to SwitchBot of res by dial and discovers its characteristics.
+func establish(ctx context.Context, res *Advertisement, o *options, dial func() (Peripheral, error)) (conn, error) {
+	c := newConn(res.Addr)
+	c.SetLogger(o.logger)
+	c.SetFramer(o.framer)
+	c.battery = o.battery
+	c.audit = o.auditor()
+	c.responseTimeout = o.timeouts.Response
+
+	cstart := time.Now()
+	device, err := dialPeripheral(ctx, o, res.Addr, dial)
+	if err != nil {
+		c.logger.Warn("failed to connect", "error", err, "duration", time.Since(cstart))
+		return conn{}, err
+	}
+	c.logger.Debug("connected", "duration", time.Since(cstart))
+	c.dev = device
+
+	dstart := time.Now()
+	if err := runPhase(ctx, c.addr, PhaseDiscovery, o.timeouts.Discovery, c.discover); err != nil {
+		c.logger.Warn("failed to discover", "error", err, "duration", time.Since(dstart))
+		device.Disconnect()
+		return conn{}, err
+	}
+	c.logger.Debug("discovered characteristics", "duration", time.Since(dstart))
+	return c, nil
+}
+
+// findTarget scans SwitchBot filter by addr argument like scanAddr.
+// If o has several adapters, the adapter with the strongest RSSI is selected,
+// and returned options are bound to the adapter.
+func findTarget(ctx context.Context, addr string, o *options, models ...string) (*Advertisement, *options, error) {
+	if len(o.adapters) <= 1 {
+		res, err := scanAddr(ctx, addr, o, models...)
+		return res, o, err
+	}
+
+	res, ao, err := selectAdapter(ctx, o, addr, models)
+	if err != nil {
+		return nil, o, err
+	}
+	o.battery.recordAdvertisement(o.logger, res)
+	return res, ao, nil
+}
+
+// checkModel returns ModelMismatchError if adv is identified as a model other than models.
+// SwitchBot whose model is not identified is accepted.
+func checkModel(adv *Advertisement, models []string) error {
+	if len(models) == 0 {
+		return nil
+	}
+	m := matchModel(adv)
+	if m == nil || containsModel(models, m) {
+		return nil
+	}
+	return &ModelMismatchError{Addr: adv.Addr, Model: m.Name, Expected: models}
+}
+
+// scanAddr scans until SwitchBot filter by addr argument advertises.
+// addr is address, alias or Nearest. If addr is Nearest, SwitchBot of models
+// with the strongest RSSI is selected.
+// If SwitchBot is not found within deadline of scan, scanAddr returns TimeoutError.
+// If the deadline is zero, scanAddr scans until ctx is done.
+func scanAddr(ctx context.Context, addr string, o *options, models ...string) (*Advertisement, error) {
+	if addr == Nearest {
+		res, err := scanNearest(ctx, o, models)
+		if err == nil {
+			o.battery.recordAdvertisement(o.logger, res)
+		}
+		return res, err
+	}
+
+	target, err := o.resolve(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := scanTarget(ctx, o, target, o.timeouts.Scan, nil)
+	if err != nil {
+		return nil, err
+	}
+	o.battery.recordAdvertisement(o.logger, res)
+	return res, nil
+}
+
+// scanTarget scans until SwitchBot of target advertises and match returns true
+// for the advertisement. If match is nil, the first advertisement is returned.
+// Advertisements weaker than RSSI threshold of o are ignored.
+// If such advertisement is not received within timeout, scanTarget returns TimeoutError.
+func scanTarget(ctx context.Context, o *options, target Address, timeout time.Duration, match func(adv *Advertisement) bool) (*Advertisement, error) {
+	if err := o.enable(); err != nil {
 		return nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+	ctx, cancel := phaseContext(ctx, timeout)
 	defer cancel()
 
-	var res *bluetooth.ScanResult
-	var err error
+	resc := make(chan *Advertisement, 1)
+	errc := make(chan error, 1)
+	var once sync.Once
 	go func() {
-		err = adapter.Scan(func(a *bluetooth.Adapter, sres bluetooth.ScanResult) {
-			if strings.ToUpper(sres.Address.String()) != addr {
+		errc <- o.transport.Scan(func(adv *Advertisement) {
+			if normalizeAddr(adv.Addr) != target.String() || !o.accept(adv) {
+				return
+			}
+			adv.Addr = target.String()
+			if match != nil && !match(adv) {
 				return
 			}
-			a.StopScan()
-			res = &sres
+			once.Do(func() {
+				resc <- adv
+				o.transport.StopScan()
+			})
 		})
-		if err != nil {
-			cancel()
-		}
 	}()
 
-wait:
-	for {
+	select {
+	case <-ctx.Done():
+		o.transport.StopScan()
+		<-errc
+		return nil, phaseError(ctx.Err(), target.String(), PhaseScan, timeout)
+	case res := <-resc:
+		<-errc
+		return res, nil
+	case err := <-errc:
 		select {
-		case <-ctx.Done():
-			adapter.StopScan()
-			return nil, ctx.Err()
+		case res := <-resc:
+			return res, nil
 		default:
-			if err != nil {
-				return nil, err
-			}
-			if res != nil {
-				break wait
-			}
 		}
-	}
-
-	device, err := adapter.Connect(res.Address, bluetooth.ConnectionParams{})
-	if err != nil {
+		if err == nil {
+			err = errors.New("scan stopped before SwitchBot is found")
+		}
 		return nil, err
 	}
+}
 
-	srvcs, err := device.DiscoverServices([]bluetooth.UUID{serviceUUID})
-	if err != nil {
+// scanNearest scans SwitchBots of models and returns the one with the strongest RSSI.
+// Candidates are collected during nearestScanWindow. If no candidate is found
+// within the window, the first candidate found after the window is returned.
+// If models is empty, SwitchBots of every registered model are candidates.
+func scanNearest(ctx context.Context, o *options, models []string) (*Advertisement, error) {
+	if err := o.enable(); err != nil {
 		return nil, err
 	}
 
-	var srvc *bluetooth.DeviceService
-	var bot *Bot
-	var cmdchar *bluetooth.DeviceCharacteristic
-	var subschar *bluetooth.DeviceCharacteristic
+	timeout := o.timeouts.Scan
+	ctx, cancel := phaseContext(ctx, timeout)
+	defer cancel()
 
-	for _, dsvc := range srvcs {
-		if dsvc.UUID().String() == serviceUUID.String() {
-			srvc = &dsvc
+	var mu sync.Mutex
+	var best *Advertisement
+	nearest := func() *Advertisement {
+		mu.Lock()
+		defer mu.Unlock()
+		return best
+	}
 
-			chars, err := srvc.DiscoverCharacteristics([]bluetooth.UUID{commandUUID})
-			if err != nil {
-				return nil, err
+	found := make(chan struct{})
+	errc := make(chan error, 1)
+	var once sync.Once
+	go func() {
+		errc <- o.transport.Scan(func(adv *Advertisement) {
+			m := matchModel(adv)
+			if m == nil || !containsModel(models, m) || !o.accept(adv) {
+				return
 			}
-			cmdchar = &chars[0]
+			adv.Addr = normalizeAddr(adv.Addr)
+			o.logger.Debug("nearest candidate", "addr", adv.Addr, "model", m.Name, "rssi", adv.RSSI)
 
-			chars, err = srvc.DiscoverCharacteristics([]bluetooth.UUID{subscribeUUID})
-			if err != nil {
-				return nil, err
+			mu.Lock()
+			if best == nil || adv.RSSI > best.RSSI {
+				best = adv
 			}
-			subschar = &chars[0]
+			mu.Unlock()
+			once.Do(func() {
+				close(found)
+			})
+		})
+	}()
 
-			break
+	window := time.NewTimer(nearestScanWindow)
+	defer window.Stop()
+
+	windowc := window.C
+	for windowc != nil || found != nil {
+		select {
+		case <-windowc:
+			windowc = nil
+		case <-found:
+			found = nil
+		case <-ctx.Done():
+			o.transport.StopScan()
+			<-errc
+			if res := nearest(); res != nil {
+				return res, nil
+			}
+			return nil, phaseError(ctx.Err(), Nearest, PhaseScan, timeout)
+		case err := <-errc:
+			if res := nearest(); res != nil {
+				return res, nil
+			}
+			if err == nil {
+				err = errors.New("scan stopped before SwitchBot is found")
+			}
+			return nil, err
 		}
 	}
 
-	bot = &Bot{
-		Addr: res.Address.String(),
-
-		dev: device,
+	o.transport.StopScan()
+	<-errc
+	res := nearest()
+	o.logger.Debug("nearest selected", "addr", res.Addr, "rssi", res.RSSI)
+	return res, nil
+}
 
-		cmdchar:  cmdchar,
-		subschar: subschar,
+// containsModel reports whether m is one of models.
+// If models is empty, every model is contained.
+func containsModel(models []string, m *Model) bool {
+	if len(models) == 0 {
+		return true
+	}
+	for _, name := range models {
+		if m.Name == name {
+			return true
+		}
+	}
+	return false
+}
 
-		subsque:    make(chan []byte),
-		subscribed: false,
+func newScanResult(adv *Advertisement, model *Model) *ScanResult {
+	res := &ScanResult{
+		Addr:             adv.Addr,
+		RSSI:             int(adv.RSSI),
+		Model:            model,
+		AddressType:      adv.AddressType,
+		LocalName:        adv.LocalName,
+		ManufacturerData: adv.ManufacturerData,
+		ServiceData:      adv.ServiceData,
+	}
+	if model.Decoder != nil {
+		if state, err := model.Decoder(adv.ManufacturerData, adv.ServiceData); err == nil {
+			res.State = state
+		}
 	}
-	return bot, nil
+	return res
 }
 
 func scanError(err error) error {
@@ -0,0 +1,173 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"tinygo.org/x/bluetooth"
+)
+
+func TestConnectDiscovery(t *testing.T) {
+	ft := newFakeBotTransport()
+	useTestTransport(t, ft)
+
+	bot, err := Connect(context.Background(), "11:22:33:44:55:66", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bot.cmdchar.UUID() != commandUUID || bot.subschar.UUID() != subscribeUUID {
+		t.Errorf("unexpected characteristics %s, %s", bot.cmdchar.UUID(), bot.subschar.UUID())
+	}
+	if ft.isDisconnected() {
+		t.Error("expected connection to be kept")
+	}
+}
+
+func TestConnectServiceNotFound(t *testing.T) {
+	ft := newFakeBotTransport()
+	ft.services = map[bluetooth.UUID][]bluetooth.UUID{}
+	useTestTransport(t, ft)
+
+	_, err := Connect(context.Background(), "11:22:33:44:55:66", time.Second)
+	if !errors.Is(err, ErrServiceNotFound) {
+		t.Fatalf("expected ErrServiceNotFound, got %v", err)
+	}
+	var derr *DiscoveryError
+	if !errors.As(err, &derr) || derr.UUID != serviceUUID.String() {
+		t.Errorf("unexpected error %#v", err)
+	}
+	if !ft.isDisconnected() {
+		t.Error("expected peripheral to be disconnected")
+	}
+}
+
+func TestConnectCharacteristicNotFound(t *testing.T) {
+	tests := []struct {
+		chars   []bluetooth.UUID
+		missing bluetooth.UUID
+	}{
+		{chars: []bluetooth.UUID{subscribeUUID}, missing: commandUUID},
+		{chars: []bluetooth.UUID{commandUUID}, missing: subscribeUUID},
+	}
+
+	for _, tt := range tests {
+		ft := newFakeBotTransport()
+		ft.services = map[bluetooth.UUID][]bluetooth.UUID{serviceUUID: tt.chars}
+		useTestTransport(t, ft)
+
+		_, err := Connect(context.Background(), "11:22:33:44:55:66", time.Second)
+		if !errors.Is(err, ErrCharacteristicNotFound) {
+			t.Fatalf("expected ErrCharacteristicNotFound, got %v", err)
+		}
+		var derr *DiscoveryError
+		if !errors.As(err, &derr) || derr.UUID != tt.missing.String() {
+			t.Errorf("expected %s to be missing, got %v", tt.missing.String(), err)
+		}
+		if !ft.isDisconnected() {
+			t.Error("expected peripheral to be disconnected")
+		}
+	}
+}
+
+func TestConnectModelMismatch(t *testing.T) {
+	ft := newFakeBotTransport()
+	useTestTransport(t, ft)
+
+	_, err := ConnectPlugMini(context.Background(), "11:22:33:44:55:66", time.Second)
+	var merr *ModelMismatchError
+	if !errors.As(err, &merr) {
+		t.Fatalf("expected ModelMismatchError, got %v", err)
+	}
+	if merr.Model != "Bot" || merr.Expected[0] != "PlugMini" {
+		t.Errorf("unexpected error %#v", merr)
+	}
+}
+
+func TestConnectUnknownModel(t *testing.T) {
+	ft := newFakeBotTransport()
+	ft.adv = &Advertisement{Addr: "11:22:33:44:55:66"}
+	useTestTransport(t, ft)
+
+	if _, err := ConnectPlugMini(context.Background(), "11:22:33:44:55:66", time.Second); err != nil {
+		t.Errorf("expected unidentified model to be accepted, got %v", err)
+	}
+}
+
+func TestConnectNormalizesAddress(t *testing.T) {
+	ft := newFakeBotTransport()
+	useTestTransport(t, ft)
+
+	for _, addr := range []string{"11-22-33-44-55-66", "112233445566"} {
+		bot, err := Connect(context.Background(), addr, time.Second)
+		if err != nil {
+			t.Fatalf("Connect(%q) returned error: %v", addr, err)
+		}
+		if bot.Addr != "11:22:33:44:55:66" {
+			t.Errorf("Connect(%q) returned Bot of %q", addr, bot.Addr)
+		}
+	}
+
+	if _, err := Connect(context.Background(), "11:22:33", time.Second); !errors.Is(err, ErrInvalidAddress) {
+		t.Errorf("expected ErrInvalidAddress, got %v", err)
+	}
+}
+
+func TestConnectByAlias(t *testing.T) {
+	useTestTransport(t, newFakeBotTransport())
+	useTestAliases(t, Aliases{"kitchen": "11:22:33:44:55:66"})
+
+	bot, err := Connect(context.Background(), "kitchen", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bot.Addr != "11:22:33:44:55:66" {
+		t.Errorf("unexpected address %q", bot.Addr)
+	}
+}
+
+func TestConnectNearest(t *testing.T) {
+	orig := nearestScanWindow
+	nearestScanWindow = 10 * time.Millisecond
+	t.Cleanup(func() {
+		nearestScanWindow = orig
+	})
+
+	ft := newFakeBotTransport()
+	ft.adv.RSSI = -80
+	ft.others = []*Advertisement{
+		{Addr: "22:33:44:55:66:77", RSSI: -50, LocalName: "WoHand", ServiceData: []byte{0x48, 0x90, 0xcf}},
+		// Plug Mini is stronger, but it is not a candidate of Bot.
+		{Addr: "33:44:55:66:77:88", RSSI: -30, ManufacturerData: make([]byte, 14), ServiceData: []byte{0x67, 0x00}},
+		{Addr: "44:55:66:77:88:99", RSSI: -70, LocalName: "WoHand", ServiceData: []byte{0x48, 0x90, 0xcf}},
+	}
+	useTestTransport(t, ft)
+
+	bot, err := Connect(context.Background(), Nearest, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bot.Addr != "22:33:44:55:66:77" {
+		t.Errorf("expected the strongest Bot, got %q", bot.Addr)
+	}
+}
+
+func TestDeviceSendWithoutWait(t *testing.T) {
+	ft := newFakeBotTransport()
+	useTestTransport(t, ft)
+
+	dev, err := ConnectDevice(context.Background(), "11:22:33:44:55:66", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dev.Disconnect()
+
+	res, err := dev.Send([]byte{0x57, 0x01}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != nil || len(ft.written()) != 1 {
+		t.Errorf("expected command to be written without response, got %x", res)
+	}
+}
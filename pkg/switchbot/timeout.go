@@ -0,0 +1,113 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Phases of connection and command reported by TimeoutError.
+const (
+	PhaseScan      = "scan"
+	PhaseConnect   = "connect"
+	PhaseDiscovery = "discovery"
+	PhaseResponse  = "response"
+)
+
+// Timeouts represents deadlines of each phase of connection and command.
+// Zero value of a field means the phase has no deadline.
+type Timeouts struct {
+	// Scan is deadline to find SwitchBot before connection.
+	// It is used only if timeout argument of Connect functions is zero.
+	Scan time.Duration
+	// Connect is deadline to establish BLE connection.
+	Connect time.Duration
+	// Discovery is deadline to discover SwitchBot service and characteristics.
+	Discovery time.Duration
+	// Response is deadline to receive response of each command.
+	Response time.Duration
+}
+
+// SetTimeouts sets deadlines of phases used by Connect functions and devices connected after the call.
+// Only scan has deadline by default.
+func SetTimeouts(t Timeouts) {
+	defaultClient.configure(WithTimeouts(t))
+}
+
+// CurrentTimeouts returns deadlines of phases set by SetTimeouts.
+func CurrentTimeouts() Timeouts {
+	return defaultClient.current().timeouts
+}
+
+// phaseContext returns ctx with timeout. If timeout is zero, ctx has no additional deadline.
+func phaseContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// phaseError converts deadline exceeded of the phase to TimeoutError.
+func phaseError(err error, addr, phase string, timeout time.Duration) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &TimeoutError{Addr: addr, Phase: phase, Timeout: timeout}
+	}
+	return err
+}
+
+// connectPeripheral connects to addr by Transport of o within deadline of connection.
+// Peripheral connected after the deadline is disconnected.
+func connectPeripheral(ctx context.Context, o *options, addr string) (Peripheral, error) {
+	return dialPeripheral(ctx, o, addr, func() (Peripheral, error) {
+		return o.connect(addr)
+	})
+}
+
+// dialPeripheral connects to addr by dial within deadline of connection like connectPeripheral.
+func dialPeripheral(ctx context.Context, o *options, addr string, dial func() (Peripheral, error)) (Peripheral, error) {
+	type result struct {
+		dev Peripheral
+		err error
+	}
+	resc := make(chan result, 1)
+	go func() {
+		dev, err := dial()
+		resc <- result{dev: dev, err: err}
+	}()
+
+	timeout := o.timeouts.Connect
+	ctx, cancel := phaseContext(ctx, timeout)
+	defer cancel()
+
+	select {
+	case r := <-resc:
+		return r.dev, r.err
+	case <-ctx.Done():
+		go func() {
+			if r := <-resc; r.err == nil {
+				r.dev.Disconnect()
+			}
+		}()
+		return nil, phaseError(ctx.Err(), addr, PhaseConnect, timeout)
+	}
+}
+
+// runPhase runs f and returns TimeoutError if f does not finish within timeout.
+// f keeps running after timeout, so caller must release resources used by f,
+// such as disconnecting Peripheral.
+func runPhase(ctx context.Context, addr, phase string, timeout time.Duration, f func() error) error {
+	errc := make(chan error, 1)
+	go func() {
+		errc <- f()
+	}()
+
+	ctx, cancel := phaseContext(ctx, timeout)
+	defer cancel()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		return phaseError(ctx.Err(), addr, phase, timeout)
+	}
+}
@@ -0,0 +1,121 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func useTestTimeouts(t *testing.T, to Timeouts) {
+	t.Helper()
+	prev := CurrentTimeouts()
+	SetTimeouts(to)
+	t.Cleanup(func() {
+		SetTimeouts(prev)
+	})
+}
+
+func TestConnectTimeouts(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(ft *fakeTransport)
+		addr     string
+		phase    string
+		timeouts Timeouts
+	}{
+		{
+			name:  "scan",
+			setup: func(ft *fakeTransport) {},
+			addr:  "AA:BB:CC:DD:EE:FF",
+			phase: PhaseScan,
+		},
+		{
+			name:     "connect",
+			setup:    func(ft *fakeTransport) { ft.connectDelay = time.Second },
+			addr:     "11:22:33:44:55:66",
+			phase:    PhaseConnect,
+			timeouts: Timeouts{Connect: 10 * time.Millisecond},
+		},
+		{
+			name:     "discovery",
+			setup:    func(ft *fakeTransport) { ft.discoverDelay = time.Second },
+			addr:     "11:22:33:44:55:66",
+			phase:    PhaseDiscovery,
+			timeouts: Timeouts{Discovery: 10 * time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ft := newFakeBotTransport()
+			tt.setup(ft)
+			useTestTransport(t, ft)
+			useTestTimeouts(t, tt.timeouts)
+
+			_, err := Connect(context.Background(), tt.addr, 10*time.Millisecond)
+			var terr *TimeoutError
+			if !errors.As(err, &terr) {
+				t.Fatalf("expected TimeoutError, got %v", err)
+			}
+			if terr.Phase != tt.phase || terr.Addr != tt.addr {
+				t.Errorf("unexpected error %#v", terr)
+			}
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Error("expected error to wrap context.DeadlineExceeded")
+			}
+		})
+	}
+}
+
+func TestConnectTimeoutsDisconnectLatePeripheral(t *testing.T) {
+	ft := newFakeBotTransport()
+	ft.connectDelay = 50 * time.Millisecond
+	useTestTransport(t, ft)
+	useTestTimeouts(t, Timeouts{Connect: 10 * time.Millisecond})
+
+	if _, err := Connect(context.Background(), "11:22:33:44:55:66", time.Second); err == nil {
+		t.Fatal("expected error")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for !ft.isDisconnected() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected peripheral connected after timeout to be disconnected")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestResponseTimeout(t *testing.T) {
+	ft := newFakeBotTransport()
+	ft.silent = true
+	useTestTransport(t, ft)
+	useTestTimeouts(t, Timeouts{Response: 10 * time.Millisecond})
+
+	bot, err := Connect(context.Background(), "11:22:33:44:55:66", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bot.Disconnect()
+
+	err = bot.Press(true)
+	var terr *TimeoutError
+	if !errors.As(err, &terr) || terr.Phase != PhaseResponse {
+		t.Fatalf("expected TimeoutError of response, got %v", err)
+	}
+	if err := bot.Press(false); err != nil {
+		t.Errorf("unexpected error without waiting response: %v", err)
+	}
+}
+
+func TestScanTimeoutFromTimeouts(t *testing.T) {
+	useTestTransport(t, newFakeBotTransport())
+	useTestTimeouts(t, Timeouts{Scan: 10 * time.Millisecond})
+
+	_, err := Connect(context.Background(), "AA:BB:CC:DD:EE:FF", 0)
+	var terr *TimeoutError
+	if !errors.As(err, &terr) || terr.Phase != PhaseScan || terr.Timeout != 10*time.Millisecond {
+		t.Fatalf("expected TimeoutError of scan, got %v", err)
+	}
+}
@@ -0,0 +1,250 @@
+package switchbot
+
+import (
+	"fmt"
+	"sync"
+
+	"tinygo.org/x/bluetooth"
+)
+
+// Transport represents BLE stack which Scan, Connect and devices are built on.
+type Transport interface {
+	// Enable enables the BLE stack.
+	Enable() error
+	// Scan starts scanning and blocks until StopScan is called.
+	// Callback function will be executed with every received advertisement.
+	Scan(callback func(adv *Advertisement)) error
+	// StopScan stops scanning started by Scan.
+	StopScan() error
+	// Connect connects to the peripheral specified by addr.
+	Connect(addr string) (Peripheral, error)
+}
+
+// ParamsConnector is implemented by Transport which accepts connection parameters.
+type ParamsConnector interface {
+	// ConnectWithParams connects to the peripheral specified by addr with params.
+	ConnectWithParams(addr string, params bluetooth.ConnectionParams) (Peripheral, error)
+}
+
+// DirectConnector is implemented by Transport which connects to peripheral without scan,
+// such as SwitchBot remembered by DeviceCache.
+type DirectConnector interface {
+	// ConnectDirect connects to the peripheral specified by addr of addrType with params.
+	// addrType is AddressTypePublic or AddressTypeRandom.
+	ConnectDirect(addr, addrType string, params bluetooth.ConnectionParams) (Peripheral, error)
+}
+
+// Peripheral represents connected BLE peripheral.
+type Peripheral interface {
+	// DiscoverServices discovers services filter by uuids.
+	// If uuids is nil, all services are discovered.
+	DiscoverServices(uuids []bluetooth.UUID) ([]Service, error)
+	// Disconnect disconnects the peripheral.
+	Disconnect() error
+}
+
+// Service represents GATT service of Peripheral.
+type Service interface {
+	UUID() bluetooth.UUID
+	// DiscoverCharacteristics discovers characteristics filter by uuids.
+	// If uuids is nil, all characteristics are discovered.
+	DiscoverCharacteristics(uuids []bluetooth.UUID) ([]Characteristic, error)
+}
+
+// Characteristic represents GATT characteristic of Service.
+type Characteristic interface {
+	UUID() bluetooth.UUID
+	WriteWithoutResponse(p []byte) (int, error)
+	EnableNotifications(callback func(buf []byte)) error
+	Read(data []byte) (int, error)
+}
+
+// Address types of Advertisement.
+const (
+	AddressTypePublic = "public"
+	AddressTypeRandom = "random"
+)
+
+// Advertisement represents advertisement received from SwitchBot.
+type Advertisement struct {
+	Addr string
+	// AddressType is AddressTypePublic or AddressTypeRandom. Empty if unknown.
+	AddressType string
+	RSSI        int16
+	LocalName   string
+
+	// ManufacturerData is manufacturer data of Woan Technology.
+	ManufacturerData []byte
+	// ServiceData is service data of SwitchBot service.
+	ServiceData []byte
+
+	// cached reports whether the advertisement is remembered by DeviceCache
+	// instead of received by scan, so that state in it may be outdated.
+	cached bool
+}
+
+// adapterTransport is Transport built on bluetooth.Adapter.
+type adapterTransport struct {
+	adapter *bluetooth.Adapter
+	// id is ID of adapter, which GATT properties are looked up with. Empty is the default adapter.
+	id string
+
+	mu sync.Mutex
+	// addrs maps address of SwitchBots found by scan to their address with its type.
+	addrs map[string]bluetooth.Address
+}
+
+// NewAdapterTransport initializes Transport which uses adapter.
+func NewAdapterTransport(adapter *bluetooth.Adapter) Transport {
+	return newAdapterTransport("", adapter)
+}
+
+func newAdapterTransport(id string, adapter *bluetooth.Adapter) *adapterTransport {
+	return &adapterTransport{
+		adapter: adapter,
+		id:      id,
+		addrs:   make(map[string]bluetooth.Address),
+	}
+}
+
+func (t *adapterTransport) Enable() error {
+	return t.adapter.Enable()
+}
+
+func (t *adapterTransport) Scan(callback func(adv *Advertisement)) error {
+	return t.adapter.Scan(func(a *bluetooth.Adapter, res bluetooth.ScanResult) {
+		adv := newAdvertisement(res)
+		// Only SwitchBots are remembered, not to grow with every device nearby.
+		if matchModel(adv) != nil {
+			t.mu.Lock()
+			t.addrs[normalizeAddr(adv.Addr)] = res.Address
+			t.mu.Unlock()
+		}
+		callback(adv)
+	})
+}
+
+func (t *adapterTransport) StopScan() error {
+	return t.adapter.StopScan()
+}
+
+func (t *adapterTransport) Connect(addr string) (Peripheral, error) {
+	return t.ConnectWithParams(addr, bluetooth.ConnectionParams{})
+}
+
+func (t *adapterTransport) ConnectWithParams(addr string, params bluetooth.ConnectionParams) (Peripheral, error) {
+	t.mu.Lock()
+	baddr, ok := t.addrs[normalizeAddr(addr)]
+	t.mu.Unlock()
+	if !ok {
+		return t.ConnectDirect(addr, AddressTypePublic, params)
+	}
+	return t.connect(baddr, params)
+}
+
+func (t *adapterTransport) ConnectDirect(addr, addrType string, params bluetooth.ConnectionParams) (Peripheral, error) {
+	mac, err := bluetooth.ParseMAC(addr)
+	if err != nil {
+		return nil, err
+	}
+	baddr := bluetooth.Address{MACAddress: bluetooth.MACAddress{MAC: mac}}
+	baddr.SetRandom(addrType == AddressTypeRandom)
+	return t.connect(baddr, params)
+}
+
+func (t *adapterTransport) connect(baddr bluetooth.Address, params bluetooth.ConnectionParams) (Peripheral, error) {
+	dev, err := t.adapter.Connect(baddr, params)
+	if err != nil {
+		return nil, err
+	}
+	return &adapterPeripheral{dev: dev, addr: baddr.String(), id: t.id}, nil
+}
+
+type adapterPeripheral struct {
+	dev  bluetooth.Device
+	addr string
+	id   string
+
+	// flags are GATT properties of characteristics keyed by gattFlagsKey,
+	// which are fetched once when they are required first.
+	flagsOnce sync.Once
+	flags     map[string][]string
+	flagsErr  error
+}
+
+func (p *adapterPeripheral) DiscoverServices(uuids []bluetooth.UUID) ([]Service, error) {
+	srvcs, err := p.dev.DiscoverServices(uuids)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]Service, 0, len(srvcs))
+	for _, s := range srvcs {
+		ret = append(ret, &adapterService{srvc: s, p: p})
+	}
+	return ret, nil
+}
+
+func (p *adapterPeripheral) Disconnect() error {
+	return p.dev.Disconnect()
+}
+
+// characteristicFlags returns GATT properties of characteristic char of service srvc.
+func (p *adapterPeripheral) characteristicFlags(srvc, char bluetooth.UUID) ([]string, error) {
+	p.flagsOnce.Do(func() {
+		p.flags, p.flagsErr = gattFlags(p.id, p.addr)
+	})
+	if p.flagsErr != nil {
+		return nil, p.flagsErr
+	}
+	flags, ok := p.flags[gattFlagsKey(srvc.String(), char.String())]
+	if !ok {
+		return nil, fmt.Errorf("characteristic %s of %s is not found", char.String(), p.addr)
+	}
+	return flags, nil
+}
+
+type adapterService struct {
+	srvc bluetooth.DeviceService
+	p    *adapterPeripheral
+}
+
+func (s *adapterService) UUID() bluetooth.UUID {
+	return s.srvc.UUID()
+}
+
+func (s *adapterService) DiscoverCharacteristics(uuids []bluetooth.UUID) ([]Characteristic, error) {
+	chars, err := s.srvc.DiscoverCharacteristics(uuids)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]Characteristic, 0, len(chars))
+	for _, c := range chars {
+		ret = append(ret, &adapterCharacteristic{char: c, srvc: s})
+	}
+	return ret, nil
+}
+
+type adapterCharacteristic struct {
+	char bluetooth.DeviceCharacteristic
+	srvc *adapterService
+}
+
+func (c *adapterCharacteristic) UUID() bluetooth.UUID {
+	return c.char.UUID()
+}
+
+func (c *adapterCharacteristic) WriteWithoutResponse(p []byte) (int, error) {
+	return c.char.WriteWithoutResponse(p)
+}
+
+func (c *adapterCharacteristic) EnableNotifications(callback func(buf []byte)) error {
+	return c.char.EnableNotifications(callback)
+}
+
+func (c *adapterCharacteristic) Read(data []byte) (int, error) {
+	return c.char.Read(data)
+}
+
+func (c *adapterCharacteristic) Properties() ([]string, error) {
+	return c.srvc.p.characteristicFlags(c.srvc.UUID(), c.char.UUID())
+}
@@ -0,0 +1,170 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Default values of VerifyOptions.
+const (
+	DefaultVerifyConnectTimeout = 10 * time.Second
+	DefaultVerifyWindow         = 5 * time.Second
+	DefaultVerifyAttempts       = 3
+)
+
+// VerifyOptions configures SwitchVerified.
+// Zero values are replaced with default values.
+type VerifyOptions struct {
+	// ConnectTimeout is timeout of each connection.
+	ConnectTimeout time.Duration
+	// Window is duration to wait for advertisement which reports expected state after each command.
+	Window time.Duration
+	// Attempts is maximum number of commands.
+	Attempts int
+	// Framer frames commands, such as PasswordFramer of Bot protected by password.
+	Framer Framer
+	// IfNeeded skips command if SwitchBot already advertises expected state on connect.
+	IfNeeded bool
+}
+
+// VerificationFailedError is returned when SwitchBot did not advertise expected state
+// after commands.
+type VerificationFailedError struct {
+	Addr     string
+	On       bool
+	Attempts int
+}
+
+func (e *VerificationFailedError) Error() string {
+	state := "off"
+	if e.On {
+		state = "on"
+	}
+	return fmt.Sprintf("%s did not turn %s after %d attempts", e.Addr, state, e.Attempts)
+}
+
+// switchStater is implemented by states which report whether the switch is on.
+type switchStater interface {
+	// switchOn reports whether the switch is on. ok is false if the state is unknown.
+	switchOn() (on bool, ok bool)
+}
+
+func (s *BotState) switchOn() (bool, bool) {
+	return s.On, s.StateMode
+}
+
+func (i *PlugMiniInfo) switchOn() (bool, bool) {
+	return i.On, true
+}
+
+func (i *HumidifierInfo) switchOn() (bool, bool) {
+	return i.On, true
+}
+
+func (i *LightInfo) switchOn() (bool, bool) {
+	return i.On, true
+}
+
+// advertisedSwitchState returns switch state decoded from adv.
+func advertisedSwitchState(adv *Advertisement) (on bool, ok bool) {
+	m := matchModel(adv)
+	if m == nil || m.Decoder == nil {
+		return false, false
+	}
+	state, err := m.Decoder(adv.ManufacturerData, adv.ServiceData)
+	if err != nil {
+		return false, false
+	}
+	ss, ok := state.(switchStater)
+	if !ok {
+		return false, false
+	}
+	return ss.switchOn()
+}
+
+// SwitchVerified connects to SwitchBot filter by addr argument, executes on or off command
+// and confirms that advertisement of the SwitchBot reports expected state.
+// If the state is not reported within Window, the command is retried up to Attempts.
+// SwitchBot must be a model which advertises its switch state, such as Bot in on/off mode
+// and Plug Mini. It reports whether the command was executed.
+// If the state is not reached, SwitchVerified returns VerificationFailedError.
+func SwitchVerified(ctx context.Context, addr string, on bool, opts *VerifyOptions) (bool, error) {
+	return defaultClient.SwitchVerified(ctx, addr, on, opts)
+}
+
+// SwitchVerified is SwitchVerified with options of the client and opts.
+func (c *Client) SwitchVerified(ctx context.Context, addr string, on bool, vopts *VerifyOptions, opts ...Option) (bool, error) {
+	o := VerifyOptions{}
+	if vopts != nil {
+		o = *vopts
+	}
+	if o.ConnectTimeout <= 0 {
+		o.ConnectTimeout = DefaultVerifyConnectTimeout
+	}
+	if o.Window <= 0 {
+		o.Window = DefaultVerifyWindow
+	}
+	if o.Attempts <= 0 {
+		o.Attempts = DefaultVerifyAttempts
+	}
+
+	name := "off"
+	if on {
+		name = "on"
+	}
+
+	so, err := c.options(opts)
+	if err != nil {
+		return false, err
+	}
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	// State is verified by advertisement received by scan, not by device cache which may be outdated.
+	copts := append(opts[:len(opts):len(opts)], WithScanTimeout(o.ConnectTimeout), WithDeviceCache(nil))
+
+	target := addr
+	for attempt := 1; attempt <= o.Attempts; attempt++ {
+		res, cn, err := c.connect(ctx, addr, copts)
+		if err != nil {
+			return attempt > 1, err
+		}
+		target = res.Addr
+		if cur, ok := advertisedSwitchState(res); !ok {
+			cn.Disconnect()
+			return attempt > 1, fmt.Errorf("state of %s can not be verified by its advertisement", res.Addr)
+		} else if cur == on && o.IfNeeded && attempt == 1 {
+			cn.Disconnect()
+			cn.logger.Info("skipped redundant command", "command", name)
+			return false, nil
+		}
+
+		dev := &Device{Addr: res.Addr, Model: matchModel(res), conn: cn}
+		if o.Framer != nil {
+			dev.SetFramer(o.Framer)
+		}
+		_, err = dev.Exec(name, true)
+		dev.Disconnect()
+		if err != nil {
+			return true, err
+		}
+
+		_, err = scanTarget(ctx, so, Address(res.Addr), o.Window, func(adv *Advertisement) bool {
+			cur, ok := advertisedSwitchState(adv)
+			return ok && cur == on
+		})
+		if err == nil {
+			cn.logger.Info("verified state", "command", name, "attempts", attempt)
+			return true, nil
+		}
+		if ctx.Err() != nil {
+			return true, ctx.Err()
+		}
+		if !errors.Is(err, context.DeadlineExceeded) {
+			return true, err
+		}
+		cn.logger.Warn("state is not verified", "command", name, "attempt", attempt)
+	}
+	return true, &VerificationFailedError{Addr: target, On: on, Attempts: o.Attempts}
+}
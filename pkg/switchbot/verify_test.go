@@ -0,0 +1,122 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var testVerifyOptions = &VerifyOptions{
+	ConnectTimeout: time.Second,
+	Window:         50 * time.Millisecond,
+	Attempts:       2,
+}
+
+func newFakeOffBotTransport() *fakeTransport {
+	ft := newFakeBotTransport()
+	ft.adv.ServiceData = []byte{0x48, 0xc0, 0xcf}
+	return ft
+}
+
+func TestSwitchVerified(t *testing.T) {
+	ft := newFakeOffBotTransport()
+	ft.onWrite = func(p []byte) {
+		ft.adv = &Advertisement{Addr: ft.adv.Addr, ServiceData: []byte{0x48, 0x80, 0xcf}}
+	}
+	useTestTransport(t, ft)
+
+	changed, err := SwitchVerified(context.Background(), "11:22:33:44:55:66", true, testVerifyOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed || len(ft.written()) != 1 {
+		t.Errorf("expected single command, got changed %t and %d writes", changed, len(ft.written()))
+	}
+}
+
+func TestSwitchVerifiedFailed(t *testing.T) {
+	ft := newFakeOffBotTransport()
+	useTestTransport(t, ft)
+
+	_, err := SwitchVerified(context.Background(), "11:22:33:44:55:66", true, testVerifyOptions)
+	var verr *VerificationFailedError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected VerificationFailedError, got %v", err)
+	}
+	if verr.Attempts != 2 || !verr.On || verr.Addr != "11:22:33:44:55:66" {
+		t.Errorf("unexpected error %+v", verr)
+	}
+	if n := len(ft.written()); n != 2 {
+		t.Errorf("expected command to be retried, got %d writes", n)
+	}
+}
+
+func TestSwitchVerifiedIfNeeded(t *testing.T) {
+	ft := newFakeOffBotTransport()
+	useTestTransport(t, ft)
+
+	opts := *testVerifyOptions
+	opts.IfNeeded = true
+	changed, err := SwitchVerified(context.Background(), "11:22:33:44:55:66", false, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed || len(ft.written()) != 0 {
+		t.Errorf("expected command to be skipped, got changed %t", changed)
+	}
+}
+
+func TestSwitchVerifiedPressMode(t *testing.T) {
+	ft := newFakeBotTransport()
+	ft.adv.ServiceData = []byte{0x48, 0x00, 0xcf}
+	useTestTransport(t, ft)
+
+	if _, err := SwitchVerified(context.Background(), "11:22:33:44:55:66", true, testVerifyOptions); err == nil {
+		t.Error("expected error for Bot in press mode")
+	}
+	if n := len(ft.written()); n != 0 {
+		t.Errorf("expected no command, got %d writes", n)
+	}
+}
+
+func TestClientSwitchVerified(t *testing.T) {
+	ft := newFakeOffBotTransport()
+	ft.onWrite = func(p []byte) {
+		ft.adv = &Advertisement{Addr: ft.adv.Addr, ServiceData: []byte{0x48, 0x80, 0xcf}}
+	}
+	c := NewClient(WithTransport(ft), WithAliases(Aliases{"kitchen": "11:22:33:44:55:66"}))
+	defer c.Close()
+
+	changed, err := c.SwitchVerified(context.Background(), "kitchen", true, testVerifyOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed || len(ft.written()) != 1 {
+		t.Errorf("expected single command by transport of client, got changed %t and %d writes", changed, len(ft.written()))
+	}
+}
+
+func TestSwitchVerifiedIfNeededWithDeviceCache(t *testing.T) {
+	ft := newFakeOffBotTransport()
+	ft.onWrite = func(p []byte) {
+		ft.adv = &Advertisement{Addr: ft.adv.Addr, ServiceData: []byte{0x48, 0x80, 0xcf}}
+	}
+	cache := NewDeviceCache(0)
+	cache.Put(&Advertisement{Addr: ft.adv.Addr, ServiceData: []byte{0x48, 0x80, 0xcf}})
+	c := NewClient(WithTransport(ft), WithDeviceCache(cache))
+	defer c.Close()
+
+	opts := *testVerifyOptions
+	opts.IfNeeded = true
+	changed, err := c.SwitchVerified(context.Background(), "11:22:33:44:55:66", true, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed || len(ft.written()) != 1 {
+		t.Errorf("expected cached on state to be ignored, got changed %t and %d writes", changed, len(ft.written()))
+	}
+	if len(ft.directs) != 0 {
+		t.Errorf("expected device cache to be bypassed, got %d direct connections", len(ft.directs))
+	}
+}